@@ -29,6 +29,7 @@ func main() {
 
 	g.Highlight = true
 	g.SelFgColor = gocui.ColorRed
+	g.Mouse = true
 
 	g.SetManagerFunc(layout)
 
@@ -46,7 +47,7 @@ func main() {
 
 func layout(g *gocui.Gui) error {
 	maxX, _ := g.Size()
-	v, err := g.SetView("help", maxX-25, 0, maxX-1, 9)
+	v, err := g.SetView("help", maxX-25, 0, maxX-1, 10)
 	if err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
@@ -55,6 +56,7 @@ func layout(g *gocui.Gui) error {
 		fmt.Fprintln(v, "Space: New View")
 		fmt.Fprintln(v, "Tab: Next View")
 		fmt.Fprintln(v, "← ↑ → ↓: Move View")
+		fmt.Fprintln(v, "Mouse: Move/Resize View")
 		fmt.Fprintln(v, "Backspace: Delete View")
 		fmt.Fprintln(v, "t: Set view on top")
 		fmt.Fprintln(v, "b: Set view on bottom")
@@ -88,25 +90,25 @@ func initKeybindings(g *gocui.Gui) error {
 		}); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", gocui.KeyArrowLeft, gocui.ModNone,
+	if err := g.SetKeybinding("v*", gocui.KeyArrowLeft, gocui.ModNone,
 		func(g *gocui.Gui, v *gocui.View) error {
 			return moveView(g, v, -delta, 0)
 		}); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", gocui.KeyArrowRight, gocui.ModNone,
+	if err := g.SetKeybinding("v*", gocui.KeyArrowRight, gocui.ModNone,
 		func(g *gocui.Gui, v *gocui.View) error {
 			return moveView(g, v, delta, 0)
 		}); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", gocui.KeyArrowDown, gocui.ModNone,
+	if err := g.SetKeybinding("v*", gocui.KeyArrowDown, gocui.ModNone,
 		func(g *gocui.Gui, v *gocui.View) error {
 			return moveView(g, v, 0, delta)
 		}); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", gocui.KeyArrowUp, gocui.ModNone,
+	if err := g.SetKeybinding("v*", gocui.KeyArrowUp, gocui.ModNone,
 		func(g *gocui.Gui, v *gocui.View) error {
 			return moveView(g, v, 0, -delta)
 		}); err != nil {
@@ -138,6 +140,9 @@ func newView(g *gocui.Gui) error {
 			return err
 		}
 		v.Wrap = true
+		v.Draggable = true
+		v.Resizable = true
+		v.MinWidth, v.MinHeight = 4, 1
 		fmt.Fprintln(v, strings.Repeat(name+" ", 30))
 	}
 	if _, err := g.SetCurrentView(name); err != nil {
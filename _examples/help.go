@@ -0,0 +1,131 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jroimartin/gocui"
+)
+
+type binding struct {
+	view        string
+	key         interface{}
+	handler     func(*gocui.Gui, *gocui.View) error
+	category    string
+	description string
+}
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Highlight = true
+	g.SelFgColor = gocui.ColorGreen
+
+	help := gocui.NewHelpOverlay("help")
+	g.SetManager(gocui.ManagerFunc(layout), help)
+
+	bindings := []binding{
+		{"", gocui.KeyCtrlC, quit, "General", "Quit"},
+		{"", gocui.KeyF1, help.Toggle, "General", "Toggle help"},
+		{"", gocui.KeyTab, nextView, "General", "Next view"},
+		{"list", gocui.KeyArrowDown, cursorDown, "List", "Next item"},
+		{"list", gocui.KeyArrowUp, cursorUp, "List", "Previous item"},
+		{"list", gocui.KeyEnter, selectItem, "List", "Select item"},
+		{"log", gocui.KeyCtrlL, clearLog, "Log", "Clear log"},
+	}
+	for _, b := range bindings {
+		if err := g.SetKeybinding(b.view, b.key, gocui.ModNone, b.handler); err != nil {
+			log.Panicln(err)
+		}
+		if err := g.DescribeKeybinding(b.view, b.key, gocui.ModNone, b.category, b.description); err != nil {
+			log.Panicln(err)
+		}
+	}
+
+	if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
+		log.Panicln(err)
+	}
+}
+
+func layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	if v, err := g.SetView("list", 0, 0, maxX/2-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "List (F1 for help)"
+		v.Highlight = true
+		v.SelBgColor = gocui.ColorGreen
+		v.SelFgColor = gocui.ColorBlack
+		for i := 0; i < 10; i++ {
+			fmt.Fprintf(v, "Item %d\n", i)
+		}
+		if _, err := g.SetCurrentView("list"); err != nil {
+			return err
+		}
+	}
+	if v, err := g.SetView("log", maxX/2, 0, maxX-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Log"
+		v.Autoscroll = true
+	}
+	return nil
+}
+
+func nextView(g *gocui.Gui, v *gocui.View) error {
+	next := "list"
+	if v != nil && v.Name() == "list" {
+		next = "log"
+	}
+	_, err := g.SetCurrentView(next)
+	return err
+}
+
+func cursorDown(g *gocui.Gui, v *gocui.View) error {
+	cx, cy := v.Cursor()
+	if l, err := v.Line(cy + 1); err != nil || l == "" {
+		return nil
+	}
+	return v.SetCursor(cx, cy+1)
+}
+
+func cursorUp(g *gocui.Gui, v *gocui.View) error {
+	cx, cy := v.Cursor()
+	if cy == 0 {
+		return nil
+	}
+	return v.SetCursor(cx, cy-1)
+}
+
+func selectItem(g *gocui.Gui, v *gocui.View) error {
+	_, cy := v.Cursor()
+	l, err := v.Line(cy)
+	if err != nil {
+		return err
+	}
+	lv, err := g.View("log")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(lv, "Selected:", l)
+	return nil
+}
+
+func clearLog(g *gocui.Gui, v *gocui.View) error {
+	v.Clear()
+	return nil
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
@@ -0,0 +1,110 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jroimartin/gocui"
+)
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Mouse = true
+	g.MouseMotion = true
+
+	g.SetManagerFunc(layout)
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
+		log.Panicln(err)
+	}
+}
+
+func layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	for i, name := range []string{"fruits", "colors"} {
+		x0 := i * 25
+		v, err := g.SetView(name, x0, 0, x0+23, 8)
+		if err != nil {
+			if err != gocui.ErrUnknownView {
+				return err
+			}
+			v.Title = name
+			v.SelBgColor = gocui.ColorGreen
+			v.SelFgColor = gocui.ColorBlack
+			v.OnMouseEnter = enter
+			v.OnMouseLeave = leave
+			v.OnMouseMove = move
+		}
+		v.Clear()
+		items := map[string][]string{
+			"fruits": {"apple", "banana", "cherry", "kiwi"},
+			"colors": {"red", "green", "blue"},
+		}[name]
+		for _, item := range items {
+			fmt.Fprintln(v, item)
+		}
+	}
+	if v, err := g.SetView("status", 0, maxY-2, maxX-1, maxY); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = false
+	}
+	return nil
+}
+
+func enter(g *gocui.Gui, v *gocui.View) error {
+	v.FrameFgColor = gocui.ColorGreen
+	v.Highlight = true
+	return nil
+}
+
+func leave(g *gocui.Gui, v *gocui.View) error {
+	v.FrameFgColor = gocui.ColorDefault
+	v.Highlight = false
+	return setStatus(g, "")
+}
+
+func move(g *gocui.Gui, v *gocui.View, ev *gocui.MouseEvent) error {
+	if ev.Area != gocui.AreaContent {
+		v.Highlight = false
+		return setStatus(g, "")
+	}
+	l, err := v.Line(ev.ViewY)
+	if err != nil || l == "" {
+		v.Highlight = false
+		return setStatus(g, "")
+	}
+	v.Highlight = true
+	if err := v.SetCursor(0, ev.ViewY); err != nil {
+		return err
+	}
+	return setStatus(g, "Hovering "+l)
+}
+
+func setStatus(g *gocui.Gui, s string) error {
+	v, err := g.View("status")
+	if err != nil {
+		return err
+	}
+	v.Clear()
+	fmt.Fprint(v, s)
+	return nil
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
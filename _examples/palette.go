@@ -0,0 +1,133 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+)
+
+var commands = []string{
+	"File: New File",
+	"File: Open File",
+	"File: Save",
+	"File: Save As",
+	"File: Close Editor",
+	"Edit: Undo",
+	"Edit: Redo",
+	"Edit: Find",
+	"Edit: Replace",
+	"View: Toggle Sidebar",
+	"View: Toggle Terminal",
+	"View: Zoom In",
+	"View: Zoom Out",
+	"Go: Go to Line",
+	"Go: Go to Symbol",
+	"Help: Keyboard Shortcuts",
+}
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Cursor = true
+	g.SetManagerFunc(layout)
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetKeybinding("input", gocui.KeyArrowDown, gocui.ModNone, moveSelection(1)); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetKeybinding("input", gocui.KeyArrowUp, gocui.ModNone, moveSelection(-1)); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
+		log.Panicln(err)
+	}
+}
+
+func layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	if v, err := g.SetView("input", maxX/4, 1, maxX*3/4, 3); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Command palette"
+		v.Editable = true
+		v.SingleLine = true
+		v.Placeholder = "Type to search commands"
+		v.Editor = gocui.EditorFunc(func(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+			gocui.DefaultEditor.Edit(v, key, ch, mod)
+			if cv, err := g.View("commands"); err == nil {
+				cv.RankLines(strings.TrimSpace(v.Buffer()))
+			}
+		})
+		v.OnSubmit = runCommand
+		v.OnCancel = quit
+		if _, err := g.SetCurrentView("input"); err != nil {
+			return err
+		}
+	}
+
+	if v, err := g.SetView("commands", maxX/4, 3, maxX*3/4, maxY-4); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Highlight = true
+		v.SelBgColor = gocui.ColorGreen
+		v.SelFgColor = gocui.ColorBlack
+		v.MatchFgColor = gocui.ColorYellow | gocui.AttrBold
+		fmt.Fprint(v, strings.Join(commands, "\n"))
+	}
+
+	if _, err := g.SetView("status", maxX/4, maxY-3, maxX*3/4, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+	}
+	return nil
+}
+
+func moveSelection(dy int) func(g *gocui.Gui, v *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		cv, err := g.View("commands")
+		if err != nil {
+			return err
+		}
+		cv.MoveCursor(0, dy, false)
+		return nil
+	}
+}
+
+func runCommand(g *gocui.Gui, v *gocui.View, text string) error {
+	cv, err := g.View("commands")
+	if err != nil {
+		return err
+	}
+	_, cy := cv.Cursor()
+	cmd, err := cv.Line(cy)
+	if err != nil {
+		return nil
+	}
+	sv, err := g.View("status")
+	if err != nil {
+		return err
+	}
+	sv.Clear()
+	fmt.Fprintf(sv, "Run: %s", cmd)
+	return nil
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
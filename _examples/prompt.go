@@ -0,0 +1,104 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+)
+
+var commands = []string{"clear", "echo", "exit", "help", "history"}
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Cursor = true
+
+	g.SetManagerFunc(layout)
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
+		log.Panicln(err)
+	}
+}
+
+func layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	if v, err := g.SetView("output", 0, 0, maxX-1, maxY-4); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Autoscroll = true
+		v.Wrap = true
+	}
+	if v, err := g.SetView("prompt", 0, maxY-3, maxX-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Command (Esc to quit)"
+		v.Editable = true
+		v.SingleLine = true
+		v.OnSubmit = submit
+		v.OnCancel = cancel
+		v.Placeholder = "Type a command, Tab to complete"
+		v.Completer = gocui.CompleterFunc(complete)
+		v.CompletionHint = true
+
+		path := filepath.Join(os.TempDir(), "gocui_prompt_history")
+		if v.History, err = gocui.LoadHistory(path, 100); err != nil {
+			return err
+		}
+		if _, err := g.SetCurrentView("prompt"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func submit(g *gocui.Gui, v *gocui.View, text string) error {
+	out, err := g.View("output")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "> %s\n", text)
+
+	v.Clear()
+	v.SetOrigin(0, 0)
+	return v.SetCursor(0, 0)
+}
+
+func complete(buffer string, cursor int) []gocui.Completion {
+	prefix := string([]rune(buffer)[:cursor])
+	if strings.Contains(prefix, " ") {
+		return nil
+	}
+	var items []gocui.Completion
+	for _, c := range commands {
+		if strings.HasPrefix(c, prefix) {
+			items = append(items, gocui.Completion{Text: c, End: cursor})
+		}
+	}
+	return items
+}
+
+func cancel(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
@@ -0,0 +1,86 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"log"
+
+	"github.com/jroimartin/gocui"
+)
+
+var search = gocui.NewSearchPrompt("search")
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Cursor = true
+
+	search.Options.IgnoreCase = true
+	g.SetManager(gocui.ManagerFunc(layout), search)
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetKeybinding("main", '/', gocui.ModNone, search.Open); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetKeybinding("main", 'n', gocui.ModNone, searchNext); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetKeybinding("main", 'N', gocui.ModNone, searchPrev); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetKeybinding("main", gocui.KeyEsc, gocui.ModNone, clearSearch); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
+		log.Panicln(err)
+	}
+}
+
+func layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	if v, err := g.SetView("main", 0, 0, maxX-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "/ search, n/N next/previous match, Esc clear"
+		v.Wrap = true
+		b, err := ioutil.ReadFile("Mark.Twain-Tom.Sawyer.txt")
+		if err != nil {
+			return err
+		}
+		v.Write(b)
+		if _, err := g.SetCurrentView("main"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func searchNext(g *gocui.Gui, v *gocui.View) error {
+	v.SearchNext()
+	return nil
+}
+
+func searchPrev(g *gocui.Gui, v *gocui.View) error {
+	v.SearchPrev()
+	return nil
+}
+
+func clearSearch(g *gocui.Gui, v *gocui.View) error {
+	v.ClearSearch()
+	return nil
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
@@ -0,0 +1,96 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jroimartin/gocui"
+)
+
+// split is the column of the splitter between both panes.
+var split = 30
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Mouse = true
+
+	g.SetManagerFunc(layout)
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetMouseBinding("splitter", gocui.MouseLeft, gocui.ModNone, dragSplitter); err != nil {
+		log.Panicln(err)
+	}
+	if err := g.SetMouseBinding("right", gocui.MouseLeft, gocui.ModNone, logEvent); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
+		log.Panicln(err)
+	}
+}
+
+func layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	if split < 10 {
+		split = 10
+	} else if split > maxX-10 {
+		split = maxX - 10
+	}
+
+	if v, err := g.SetView("left", 0, 0, split-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Left"
+		v.Wrap = true
+		fmt.Fprintln(v, "Drag the splitter with the mouse.")
+	}
+	if v, err := g.SetView("splitter", split-1, 0, split+1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = false
+		v.BgColor = gocui.ColorBlue
+	}
+	if v, err := g.SetView("right", split+1, 0, maxX-1, maxY-1); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Right (click and drag here)"
+		v.Autoscroll = true
+	}
+	return nil
+}
+
+func dragSplitter(g *gocui.Gui, v *gocui.View, ev *gocui.MouseEvent) error {
+	if ev.Action == gocui.MouseActionDrag {
+		split = ev.X
+	}
+	return nil
+}
+
+func logEvent(g *gocui.Gui, v *gocui.View, ev *gocui.MouseEvent) error {
+	actions := map[gocui.MouseAction]string{
+		gocui.MouseActionPress:   "press",
+		gocui.MouseActionRelease: "release",
+		gocui.MouseActionDrag:    "drag",
+	}
+	fmt.Fprintf(v, "%s at (%d, %d), view (%d, %d), clicks: %d\n",
+		actions[ev.Action], ev.X, ev.Y, ev.ViewX, ev.ViewY, ev.Clicks)
+	return nil
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
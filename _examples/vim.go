@@ -0,0 +1,75 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jroimartin/gocui"
+)
+
+var editor = gocui.NewVimEditor()
+
+func main() {
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		log.Panicln(err)
+	}
+	defer g.Close()
+
+	g.Cursor = true
+
+	editor.OnCommand = func(v *gocui.View, cmd string) {
+		if cmd == "q" {
+			g.Update(func(g *gocui.Gui) error {
+				return gocui.ErrQuit
+			})
+		}
+	}
+
+	g.SetManagerFunc(layout)
+
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
+		log.Panicln(err)
+	}
+
+	if err := g.MainLoop(); err != nil && err != gocui.ErrQuit {
+		log.Panicln(err)
+	}
+}
+
+func layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	if v, err := g.SetView("editor", 0, 0, maxX-1, maxY-2); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Title = "Vim (:q to quit)"
+		v.Editable = true
+		v.Editor = editor
+		fmt.Fprintln(v, "Edit this text with vim keybindings.")
+		if _, err := g.SetCurrentView("editor"); err != nil {
+			return err
+		}
+	}
+
+	v, err := g.SetView("status", -1, maxY-2, maxX, maxY)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	v.Frame = false
+	v.Clear()
+	if editor.Mode() == gocui.VimCommandLine {
+		fmt.Fprint(v, editor.CommandLine())
+	} else {
+		fmt.Fprintf(v, "-- %v --", editor.Mode())
+	}
+	return nil
+}
+
+func quit(g *gocui.Gui, v *gocui.View) error {
+	return gocui.ErrQuit
+}
@@ -0,0 +1,221 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrClipboardUnavailable is returned when there is no way to access the
+// clipboard.
+var ErrClipboardUnavailable = errors.New("clipboard unavailable")
+
+// ErrNotEditable is returned by Cut and Paste when the view is not editable.
+var ErrNotEditable = errors.New("view not editable")
+
+// Clipboard interface must be satisfied by gocui clipboards.
+type Clipboard interface {
+	// Copy stores text in the clipboard.
+	Copy(text string) error
+
+	// Paste returns the contents of the clipboard.
+	Paste() (string, error)
+}
+
+// MemoryClipboard is an in-process clipboard. It is useful for tests and
+// as a fallback when the system clipboard is not available.
+type MemoryClipboard struct {
+	text string
+}
+
+// Copy stores text in the clipboard.
+func (c *MemoryClipboard) Copy(text string) error {
+	c.text = text
+	return nil
+}
+
+// Paste returns the last copied text.
+func (c *MemoryClipboard) Paste() (string, error) {
+	return c.text, nil
+}
+
+// OSC52Clipboard copies text to the clipboard of the terminal emulator using
+// the OSC 52 control sequence, which also works over SSH and, if the
+// environment variable TMUX is set, inside tmux. Terminals do not allow to
+// read the clipboard reliably, so Paste returns the last copied text.
+type OSC52Clipboard struct {
+	w    io.Writer
+	last MemoryClipboard
+}
+
+// NewOSC52Clipboard returns a new OSC52Clipboard that writes the control
+// sequences to w, which must be the terminal.
+func NewOSC52Clipboard(w io.Writer) *OSC52Clipboard {
+	return &OSC52Clipboard{w: w}
+}
+
+// Copy sends text to the terminal's clipboard.
+func (c *OSC52Clipboard) Copy(text string) error {
+	c.last.Copy(text)
+
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if os.Getenv("TMUX") != "" {
+		// tmux passthrough
+		seq = "\x1bPtmux;" + strings.Replace(seq, "\x1b", "\x1b\x1b", -1) + "\x1b\\"
+	}
+	_, err := io.WriteString(c.w, seq)
+	return err
+}
+
+// Paste returns the last copied text.
+func (c *OSC52Clipboard) Paste() (string, error) {
+	return c.last.Paste()
+}
+
+// CommandClipboard accesses the system clipboard using external tools, like
+// wl-copy, xclip, xsel or pbcopy.
+type CommandClipboard struct {
+	copyCmd, pasteCmd []string
+}
+
+// clipboardTools are the supported clipboard tools, in order of preference.
+var clipboardTools = []struct {
+	env               string // environment variable required, if any
+	copyCmd, pasteCmd []string
+}{
+	{"WAYLAND_DISPLAY", []string{"wl-copy"}, []string{"wl-paste", "--no-newline"}},
+	{"DISPLAY", []string{"xclip", "-selection", "clipboard"}, []string{"xclip", "-selection", "clipboard", "-o"}},
+	{"DISPLAY", []string{"xsel", "--clipboard", "--input"}, []string{"xsel", "--clipboard", "--output"}},
+	{"", []string{"pbcopy"}, []string{"pbpaste"}},
+}
+
+// NewCommandClipboard returns a new CommandClipboard using the first
+// clipboard tool found in the system, or error ErrClipboardUnavailable if
+// there is none.
+func NewCommandClipboard() (*CommandClipboard, error) {
+	for _, t := range clipboardTools {
+		if t.env != "" && os.Getenv(t.env) == "" {
+			continue
+		}
+		if _, err := exec.LookPath(t.copyCmd[0]); err != nil {
+			continue
+		}
+		if _, err := exec.LookPath(t.pasteCmd[0]); err != nil {
+			continue
+		}
+		return &CommandClipboard{copyCmd: t.copyCmd, pasteCmd: t.pasteCmd}, nil
+	}
+	return nil, ErrClipboardUnavailable
+}
+
+// Copy stores text in the system clipboard.
+func (c *CommandClipboard) Copy(text string) error {
+	cmd := exec.Command(c.copyCmd[0], c.copyCmd[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// Paste returns the contents of the system clipboard.
+func (c *CommandClipboard) Paste() (string, error) {
+	var out bytes.Buffer
+	cmd := exec.Command(c.pasteCmd[0], c.pasteCmd[1:]...)
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// SystemClipboard copies text using both OSC 52 and, when present, a local
+// clipboard tool, so it works in local terminals and over SSH. Paste uses the
+// local tool if present, otherwise it returns the last copied text.
+type SystemClipboard struct {
+	osc52 *OSC52Clipboard
+	cmd   *CommandClipboard
+}
+
+// NewSystemClipboard returns a new SystemClipboard that writes the OSC 52
+// control sequences to w, which must be the terminal.
+func NewSystemClipboard(w io.Writer) *SystemClipboard {
+	c := &SystemClipboard{osc52: NewOSC52Clipboard(w)}
+	c.cmd, _ = NewCommandClipboard()
+	return c
+}
+
+// Copy stores text in the clipboard. It succeeds if any of the methods
+// succeeds.
+func (c *SystemClipboard) Copy(text string) error {
+	err := c.osc52.Copy(text)
+	if c.cmd != nil {
+		if cerr := c.cmd.Copy(text); cerr == nil {
+			err = nil
+		}
+	}
+	return err
+}
+
+// Paste returns the contents of the clipboard.
+func (c *SystemClipboard) Paste() (string, error) {
+	if c.cmd != nil {
+		if text, err := c.cmd.Paste(); err == nil {
+			return text, nil
+		}
+	}
+	return c.osc52.Paste()
+}
+
+// Copy copies the selected text of the view to its clipboard.
+func (v *View) Copy() error {
+	if v.Clipboard == nil {
+		return ErrClipboardUnavailable
+	}
+	if !v.sel.active {
+		return nil
+	}
+	return v.Clipboard.Copy(v.Selection())
+}
+
+// Cut copies the selected text of the view to its clipboard and removes it
+// from the view's internal buffer. If the filters of the view reject the
+// deletion, the text is only copied. It fails if the view is not editable.
+func (v *View) Cut() error {
+	if !v.Editable {
+		return ErrNotEditable
+	}
+	if err := v.Copy(); err != nil {
+		return err
+	}
+	v.EditDeleteSelection()
+	return nil
+}
+
+// Paste inserts the contents of the view's clipboard at the cursor position,
+// replacing the selected text. It fails if the view is not editable.
+func (v *View) Paste() error {
+	if !v.Editable {
+		return ErrNotEditable
+	}
+	if v.Clipboard == nil {
+		return ErrClipboardUnavailable
+	}
+	text, err := v.Clipboard.Paste()
+	if err != nil {
+		return err
+	}
+
+	v.beginEdit(editOther)
+	defer v.endEdit()
+
+	if v.deleteSelection() {
+		v.EditWriteString(text)
+	}
+	return nil
+}
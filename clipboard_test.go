@@ -0,0 +1,34 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestClipboardReadOnlyView(t *testing.T) {
+	g := &Gui{maxX: 80, maxY: 24}
+	v, _ := g.SetView("main", 0, 0, 40, 10)
+	v.Clipboard = &MemoryClipboard{}
+	fmt.Fprint(v, "foo bar")
+	v.SetSelection(0, 0, 3, 0)
+
+	if err := v.Copy(); err != nil {
+		t.Fatalf("Copy: got error %v", err)
+	}
+	if text, _ := v.Clipboard.Paste(); text != "foo" {
+		t.Errorf("Copy: got clipboard %q, want %q", text, "foo")
+	}
+	if err := v.Cut(); err != ErrNotEditable {
+		t.Errorf("Cut: got error %v, want %v", err, ErrNotEditable)
+	}
+	if err := v.Paste(); err != ErrNotEditable {
+		t.Errorf("Paste: got error %v, want %v", err, ErrNotEditable)
+	}
+	if got := v.Buffer(); got != "foo bar\n" {
+		t.Errorf("got buffer %q, want %q", got, "foo bar\n")
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "strings"
+
+// maxCompletionItems is the maximum number of candidates shown at once by the
+// completion menu.
+const maxCompletionItems = 10
+
+// Completion is a candidate to complete the text of an editable view.
+type Completion struct {
+	// Display is the text shown in the completion menu. If it is empty,
+	// Text is shown.
+	Display string
+
+	// Text replaces the text of the buffer between Start and End.
+	Text string
+
+	// Start and End are the offsets, in runes, of the text of the buffer
+	// replaced by Text.
+	Start, End int
+}
+
+// Completer interface must be satisfied by the completers of editable views.
+type Completer interface {
+	// Complete returns the candidates to complete buffer, the text of the
+	// view, with the cursor at the offset cursor, in runes. Lines are
+	// separated by '\n'.
+	Complete(buffer string, cursor int) []Completion
+}
+
+// The CompleterFunc type is an adapter to allow the use of ordinary
+// functions as Completers. If f is a function with the appropriate
+// signature, CompleterFunc(f) is a Completer object that calls f.
+type CompleterFunc func(buffer string, cursor int) []Completion
+
+// Complete calls f(buffer, cursor)
+func (f CompleterFunc) Complete(buffer string, cursor int) []Completion {
+	return f(buffer, cursor)
+}
+
+// completionMenu is the menu with the candidates of a completion.
+type completionMenu struct {
+	items []Completion
+	index int // selected candidate
+	start int // offset of the completed text, where the menu is aligned
+}
+
+// display returns the text shown for the candidate c.
+func (c Completion) display() string {
+	if c.Display != "" {
+		return c.Display
+	}
+	return c.Text
+}
+
+// completeKey handles the keystrokes that control the completion of the view.
+// It returns false if the keystroke must be passed to the editor.
+func (v *View) completeKey(key Key, mod Modifier) bool {
+	if m := v.menu; m != nil {
+		switch {
+		case key == KeyTab || key == KeyArrowDown:
+			m.index = (m.index + 1) % len(m.items)
+		case key == KeyBacktab || key == KeyArrowUp:
+			m.index = (m.index + len(m.items) - 1) % len(m.items)
+		case key == KeyEnter:
+			v.menu = nil
+			v.applyCompletion(m.items[m.index])
+		case key == KeyEsc:
+			v.menu = nil
+		default:
+			v.menu = nil
+			return false
+		}
+		return true
+	}
+
+	switch {
+	case key == KeyTab && mod == ModNone:
+		buffer, cursor := v.completionText()
+		items := v.Completer.Complete(buffer, cursor)
+		switch len(items) {
+		case 0:
+		case 1:
+			v.applyCompletion(items[0])
+		default:
+			start := cursor
+			for _, item := range items {
+				if item.Start < start {
+					start = item.Start
+				}
+			}
+			v.menu = &completionMenu{items: items, start: start}
+		}
+		v.hint = ""
+		return true
+	case key == KeyArrowRight && mod == ModNone && v.hint != "":
+		hint := v.hint
+		v.hint = ""
+		v.EditWriteString(hint)
+		return true
+	}
+	return false
+}
+
+// completionText returns the text of the view and the offset of the
+// cursor, in runes.
+func (v *View) completionText() (buffer string, cursor int) {
+	p := v.cursorPosition()
+	for y := 0; y < p.y && y < len(v.lines); y++ {
+		cursor += len(v.lines[y]) + 1
+	}
+	return strings.Join(v.BufferLines(), "\n"), cursor + p.x
+}
+
+// offsetPosition returns the point of the internal buffer at the offset
+// off, in runes, of the text of the view.
+func (v *View) offsetPosition(off int) position {
+	for y, line := range v.lines {
+		if off <= len(line) {
+			return position{off, y}
+		}
+		off -= len(line) + 1
+	}
+	return v.clampPosition(position{0, len(v.lines)})
+}
+
+// applyCompletion replaces the text of the view with the candidate c.
+func (v *View) applyCompletion(c Completion) {
+	v.beginEdit(editOther)
+	defer v.endEdit()
+
+	v.ClearSelection()
+	if v.editDeleteText(v.offsetPosition(c.Start), v.offsetPosition(c.End)) {
+		v.EditWriteString(c.Text)
+	}
+}
+
+// updateHint updates the ghost text shown after the cursor, which is the
+// rest of the first candidate if it completes the text being typed at the
+// end of the line.
+func (v *View) updateHint() {
+	v.hint = ""
+	if !v.CompletionHint || v.menu != nil || v.Mask != 0 {
+		return
+	}
+	p := v.cursorPosition()
+	if p.y >= len(v.lines) || p.x != len(v.lines[p.y]) {
+		return
+	}
+
+	buffer, cursor := v.completionText()
+	items := v.Completer.Complete(buffer, cursor)
+	if len(items) == 0 {
+		return
+	}
+	c := items[0]
+	runes := []rune(buffer)
+	if c.End != cursor || c.Start < 0 || c.Start >= cursor {
+		return
+	}
+	typed := string(runes[c.Start:cursor])
+	if strings.HasPrefix(c.Text, typed) {
+		v.hint = c.Text[len(typed):]
+	}
+}
+
+// hintColor returns the color of the ghost text.
+func (v *View) hintColor() Attribute {
+	if v.HintFgColor != ColorDefault {
+		return v.HintFgColor
+	}
+	return ColorBlack | AttrBold
+}
+
+// drawHint draws the ghost text after the cursor.
+func (v *View) drawHint() error {
+	maxX, maxY := v.Size()
+	if v.cy < 0 || v.cy >= maxY {
+		return nil
+	}
+	x := v.cx
+	for _, ch := range v.hint {
+		if x >= maxX {
+			break
+		}
+		if x >= 0 {
+			if err := v.setRune(x, v.cy, ch, v.hintColor(), v.BgColor); err != nil {
+				return err
+			}
+		}
+		x++
+	}
+	return nil
+}
+
+// drawCompletionMenu draws the completion menu of the view below the cursor,
+// or above it if there is no room, aligned with the text being completed.
+func (g *Gui) drawCompletionMenu(v *View) error {
+	m := v.menu
+	n := len(m.items)
+	if n > maxCompletionItems {
+		n = maxCompletionItems
+	}
+	first := 0
+	if m.index >= n {
+		first = m.index - n + 1
+	}
+
+	width := 0
+	for _, item := range m.items {
+		if w := len([]rune(item.display())); w > width {
+			width = w
+		}
+	}
+	width += 2
+
+	_, cursor := v.completionText()
+	cx, cy := v.x0+v.cx+1, v.y0+v.cy+1
+	x0 := cx - (cursor - m.start) - 1
+	if x0+width > g.maxX {
+		x0 = g.maxX - width
+	}
+	if x0 < 0 {
+		x0 = 0
+	}
+	y0 := cy + 1
+	if y0+n > g.maxY {
+		y0 = cy - n
+	}
+	if y0 < 0 {
+		y0 = 0
+	}
+
+	for i := 0; i < n; i++ {
+		fgColor, bgColor := v.FgColor|AttrReverse, v.BgColor
+		if first+i == m.index {
+			fgColor, bgColor = v.SelFgColor, v.SelBgColor
+			if fgColor == ColorDefault && bgColor == ColorDefault {
+				fgColor, bgColor = v.FgColor|AttrBold, v.BgColor
+			}
+		}
+		text := []rune(" " + m.items[first+i].display())
+		for x := 0; x < width && x0+x < g.maxX; x++ {
+			ch := ' '
+			if x < len(text) {
+				ch = text[x]
+			}
+			if y0+i < g.maxY {
+				if err := g.SetRune(x0+x, y0+i, ch, fgColor, bgColor); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
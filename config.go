@@ -0,0 +1,205 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrUnknownKey is returned when a key description cannot be parsed.
+	ErrUnknownKey = errors.New("unknown key")
+
+	// ErrUnknownAction is returned when a keybinding refers to an action
+	// that has not been registered.
+	ErrUnknownAction = errors.New("unknown action")
+
+	// ErrConflict is returned when a key is already bound in the same view
+	// and keymap.
+	ErrConflict = errors.New("keybinding conflict")
+
+	// ErrSyntax is returned when a line of a keybindings file is malformed.
+	ErrSyntax = errors.New("syntax error")
+)
+
+// KeybindingError describes a problem found in a line of a keybindings file.
+type KeybindingError struct {
+	Line   int    // line number, starting at 1
+	View   string // view of the section, "" for all views
+	Keymap string // keymap of the section, "" for the default one
+	Key    string // key description
+	Action string // action name
+	Err    error  // ErrUnknownKey, ErrUnknownAction, ErrConflict, ErrSyntax or path.ErrBadPattern
+}
+
+func (e *KeybindingError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: %s: %v", e.Line, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *KeybindingError) Unwrap() error {
+	return e.Err
+}
+
+// KeybindingErrors is the list of errors returned by LoadKeybindings.
+type KeybindingErrors []*KeybindingError
+
+func (errs KeybindingErrors) Error() string {
+	s := make([]string, len(errs))
+	for i, err := range errs {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// RegisterAction registers a named action, so it can be bound to keys with
+// LoadKeybindings. Registering an action with an existing name replaces it.
+func (g *Gui) RegisterAction(name string, handler func(*Gui, *View) error) error {
+	if name == "" {
+		return errors.New("invalid name")
+	}
+	if handler == nil {
+		return errors.New("invalid handler")
+	}
+	if g.actions == nil {
+		g.actions = make(map[string]func(*Gui, *View) error)
+	}
+	g.actions[name] = handler
+	return nil
+}
+
+// SetKeymap sets the active keymap. Keybindings loaded into a named keymap
+// are only active while it is selected, the ones without keymap are always
+// active.
+func (g *Gui) SetKeymap(name string) {
+	g.keymap = name
+}
+
+// Keymap returns the name of the active keymap.
+func (g *Gui) Keymap() string {
+	return g.keymap
+}
+
+// LoadKeybindings reads keybindings from r and binds them to the registered
+// actions. The input is line oriented:
+//
+//	# Comments start with '#'.
+//	ctrl+c = quit
+//
+//	[main]
+//	enter = open
+//	alt+s = save
+//
+//	[main:vim]
+//	j = down
+//
+//	[*:vim]
+//	ctrl+w = switch
+//
+// Each binding has the form "key = action", where key is any description
+// accepted by ParseKey. Section headers select the view and, optionally, the
+// keymap of the bindings that follow them; "*" means all views. Bindings
+// before the first section apply to all views in the default keymap.
+//
+// Valid bindings are always applied. If any line cannot be applied, the
+// returned error is of type KeybindingErrors.
+func (g *Gui) LoadKeybindings(r io.Reader) error {
+	var (
+		errs         KeybindingErrors
+		view, keymap string
+	)
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				errs = append(errs, &KeybindingError{Line: n, Err: ErrSyntax})
+				continue
+			}
+			view, keymap = parseSection(line[1 : len(line)-1])
+			continue
+		}
+
+		kerr := &KeybindingError{Line: n, View: view, Keymap: keymap}
+		i := strings.LastIndex(line, "=")
+		if i < 1 {
+			kerr.Err = ErrSyntax
+			errs = append(errs, kerr)
+			continue
+		}
+		kerr.Key = strings.TrimSpace(line[:i])
+		kerr.Action = strings.TrimSpace(line[i+1:])
+
+		if kerr.Err = g.bindAction(view, keymap, kerr.Key, kerr.Action); kerr.Err != nil {
+			errs = append(errs, kerr)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// parseSection returns the view and keymap of a section header.
+func parseSection(s string) (view, keymap string) {
+	view = s
+	if i := strings.Index(s, ":"); i >= 0 {
+		view, keymap = s[:i], s[i+1:]
+	}
+	view, keymap = strings.TrimSpace(view), strings.TrimSpace(keymap)
+	if view == "*" {
+		view = ""
+	}
+	return view, keymap
+}
+
+// bindAction binds the action with the given name to the key described by
+// keydesc.
+func (g *Gui) bindAction(viewname, keymap, keydesc, action string) error {
+	if err := checkViewName(viewname); err != nil {
+		return err
+	}
+	key, mod, err := ParseKey(keydesc)
+	if err != nil {
+		return err
+	}
+	k, ch, err := getKey(key)
+	if err != nil {
+		return err
+	}
+
+	handler, ok := g.actions[action]
+	if !ok {
+		return ErrUnknownAction
+	}
+
+	for _, kb := range g.keybindings {
+		if kb.match == nil && kb.viewName == viewname && kb.keymap == keymap && kb.matchKeypress(k, ch, mod) {
+			return ErrConflict
+		}
+	}
+
+	kb := newKeybinding(viewname, k, ch, mod, handler)
+	kb.keymap = keymap
+	kb.description = action
+	g.keybindings = append(g.keybindings, kb)
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		s   string
+		key interface{}
+		mod Modifier
+		err error
+	}{
+		{"q", 'q', ModNone, nil},
+		{"Q", 'Q', ModNone, nil},
+		{"+", '+', ModNone, nil},
+		{"enter", KeyEnter, ModNone, nil},
+		{"Enter", KeyEnter, ModNone, nil},
+		{"ctrl+s", KeyCtrlS, ModNone, nil},
+		{"CTRL+S", KeyCtrlS, ModNone, nil},
+		{"ctrl+space", KeyCtrlSpace, ModNone, nil},
+		{"ctrl+2", KeyCtrl2, ModNone, nil},
+		{"alt+x", 'x', ModAlt, nil},
+		{"alt+ctrl+a", KeyCtrlA, ModAlt, nil},
+		{"ctrl+left", KeyArrowLeft, ModCtrl, nil},
+		{"shift+f5", KeyF5, ModShift, nil},
+		{"shift+tab", KeyBacktab, ModNone, nil},
+		{"f20", KeyF20, ModNone, nil},
+		{"ctrl+1", nil, 0, ErrUnknownKey},
+		{"ctrl+é", nil, 0, ErrUnknownKey},
+		{"shift+a", nil, 0, ErrUnknownKey},
+		{"foo", nil, 0, ErrUnknownKey},
+		{"alt+", nil, 0, ErrUnknownKey},
+		{"", nil, 0, ErrUnknownKey},
+	}
+
+	for _, tt := range tests {
+		key, mod, err := ParseKey(tt.s)
+		if err != tt.err {
+			t.Errorf("ParseKey(%q): got error %v, want %v", tt.s, err, tt.err)
+			continue
+		}
+		if key != tt.key || mod != tt.mod {
+			t.Errorf("ParseKey(%q) = %v, %v; want %v, %v", tt.s, key, mod, tt.key, tt.mod)
+		}
+	}
+}
+
+func TestLoadKeybindings(t *testing.T) {
+	g := &Gui{}
+	nop := func(*Gui, *View) error { return nil }
+	for _, name := range []string{"quit", "open", "down"} {
+		if err := g.RegisterAction(name, nop); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// predicate keybindings do not conflict with views of the same name
+	if err := g.SetKeybindingFunc("main", MatchTag("main"), KeyEnter, ModNone, nop); err != nil {
+		t.Fatal(err)
+	}
+
+	input := `# global
+ctrl+c = quit
+
+[main]
+enter = open
+ctrl+1 = open
+x = unknown
+broken line
+
+[main:vim]
+j = down
+[*:vim]
+j = down
+[main:vim]
+j = quit
+`
+	err := g.LoadKeybindings(strings.NewReader(input))
+	var errs KeybindingErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got error %v, want KeybindingErrors", err)
+	}
+
+	wantErrs := []struct {
+		line int
+		err  error
+	}{
+		{6, ErrUnknownKey},
+		{7, ErrUnknownAction},
+		{8, ErrSyntax},
+		{15, ErrConflict},
+	}
+	if len(errs) != len(wantErrs) {
+		t.Fatalf("got errors %v, want %d errors", errs, len(wantErrs))
+	}
+	for i, want := range wantErrs {
+		if errs[i].Line != want.line || errs[i].Err != want.err {
+			t.Errorf("error %d: got line %d: %v, want line %d: %v", i, errs[i].Line, errs[i].Err, want.line, want.err)
+		}
+	}
+
+	want := []Keybinding{
+		{ViewName: "", Key: KeyCtrlC, Description: "quit"},
+		{ViewName: "main", Key: KeyEnter, Description: "open"},
+		{ViewName: "main", Keymap: "vim", Ch: 'j', Description: "down"},
+		{ViewName: "", Keymap: "vim", Ch: 'j', Description: "down"},
+	}
+	var got []Keybinding
+	for _, kb := range g.keybindings {
+		if kb.match == nil {
+			got = append(got, kb.export())
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got keybindings %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("keybinding %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
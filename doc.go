@@ -58,6 +58,82 @@ Configure keybindings:
 		// handle error
 	}
 
+Besides ModAlt, keybindings can use ModShift and ModCtrl with keys that have
+no dedicated Key, like arrows or function keys, if the terminal reports them
+using the xterm modified-key sequences. Shift+Tab is reported as KeyBacktab:
+
+	if err := g.SetKeybinding("", gocui.KeyArrowRight, gocui.ModCtrl, nextWord); err != nil {
+		// handle error
+	}
+
+A keybinding can also apply to every view whose name matches a pattern, or
+for which a predicate returns true:
+
+	if err := g.SetKeybinding("list-*", gocui.KeyEnter, gocui.ModNone, fcn); err != nil {
+		// handle error
+	}
+	if err := g.SetKeybindingFunc("lists", gocui.MatchTag("list"), gocui.KeyEnter, gocui.ModNone, fcn); err != nil {
+		// handle error
+	}
+
+When several keybindings match an event, view-specific ones are executed
+before global ones, unless SetKeybindingPriority says otherwise. The first
+handler that does not return ErrNotHandled consumes the event:
+
+	func handler(g *gocui.Gui, v *gocui.View) error {
+		if !canHandle(v) {
+			return gocui.ErrNotHandled // let other keybindings handle it
+		}
+		// ...
+		return nil
+	}
+
+The keybindings after the one consuming the event are not executed. Note
+that this changes the behaviour of existing applications: gocui used to
+execute every matching handler, in registration order. Applications that bind
+several handlers to the same key and need all of them must now merge them in
+one handler, or make all but the last one return ErrNotHandled after doing
+their work.
+
+A handler can also return ErrPassToEditor to skip the rest of keybindings and
+pass the event to the editor of the current view, so a text input can type a
+key that has a global keybinding:
+
+	func typeQ(g *gocui.Gui, v *gocui.View) error {
+		if v != nil && v.Editable {
+			return gocui.ErrPassToEditor
+		}
+		return gocui.ErrNotHandled
+	}
+
+Keybindings can also be loaded from a configuration file. Handlers are
+registered as named actions and bound with lines of the form "key = action":
+
+	if err := g.RegisterAction("quit", quit); err != nil {
+		// handle error
+	}
+	f, err := os.Open("keys.conf")
+	if err != nil {
+		// handle error
+	}
+	defer f.Close()
+	if err := g.LoadKeybindings(f); err != nil {
+		// handle error, possibly of type gocui.KeybindingErrors
+	}
+
+Keybindings can carry a category and a description, which are used by
+HelpOverlay to show the keybindings available in the current view. Only the
+keybindings with a description are shown:
+
+	help := gocui.NewHelpOverlay("help")
+	g.SetManager(mgr1, mgr2, help)
+	if err := g.SetKeybinding("", gocui.KeyF1, gocui.ModNone, help.Toggle); err != nil {
+		// handle error
+	}
+	if err := g.DescribeKeybinding("", gocui.KeyF1, gocui.ModNone, "General", "Toggle help"); err != nil {
+		// handle error
+	}
+
 gocui implements full mouse support that can be enabled with:
 
 	g.Mouse = true
@@ -68,6 +144,44 @@ Mouse events are handled like any other keybinding:
 		// handle error
 	}
 
+By default, clicking over a view moves its cursor to the pointer. This can be
+configured with ClickMode:
+
+	g.ClickMode = gocui.ClickCursor | gocui.ClickFocus
+
+Framed views can also be moved and resized with the mouse:
+
+	v.Draggable = true // drag the top edge to move the view
+	v.Resizable = true // drag the other edges and the corners to resize it
+	v.OnGeometryChange = func(g *gocui.Gui, v *gocui.View, x0, y0, x1, y1 int) error {
+		// persist the new geometry, so the manager uses it
+		return nil
+	}
+
+The text of a view can be selected with the mouse, including double and
+triple clicks to select words and lines:
+
+	v.MouseSelect = true
+	// ...
+	text := v.Selection()
+
+Views can scroll with the mouse wheel without any keybinding:
+
+	v.ScrollOnWheel = true
+	v.WheelLines = 5
+
+Mouse bindings receive a MouseEvent with the position of the pointer and the
+phase of the interaction (press, drag, release), which allows to implement
+things like draggable splitters:
+
+	err := g.SetMouseBinding("splitter", gocui.MouseLeft, gocui.ModNone,
+		func(g *gocui.Gui, v *gocui.View, ev *gocui.MouseEvent) error {
+			if ev.Action == gocui.MouseActionDrag {
+				split = ev.X
+			}
+			return nil
+		})
+
 IMPORTANT: Views can only be created, destroyed or updated in three ways: from
 the Layout function within managers, from keybinding callbacks or via
 *Gui.Update(). The reason for this is that it allows gocui to be
@@ -93,9 +207,11 @@ and customized creating a new Editor and assigning it to *View.Editor:
 
 DefaultEditor can be taken as example to create your own custom Editor:
 
-	var DefaultEditor Editor = EditorFunc(simpleEditor)
+	var DefaultEditor Editor = defaultEditor{}
+
+	type defaultEditor struct{}
 
-	func simpleEditor(v *View, key Key, ch rune, mod Modifier) {
+	func (defaultEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
 		switch {
 		case ch != 0 && mod == 0:
 			v.EditWrite(ch)
@@ -107,6 +223,95 @@ DefaultEditor can be taken as example to create your own custom Editor:
 		}
 	}
 
+	func (defaultEditor) Paste(v *View, text string) {
+		v.EditWriteString(text)
+	}
+
+Simple editors can also be written as functions, using EditorFunc.
+
+DefaultEditor selects text with Shift and the arrow keys, Ctrl-Shift and the
+horizontal arrows extend the selection by words, and Ctrl-A selects all the
+text. Note that Ctrl-A does not move the cursor to the beginning of the line,
+as in Readline; use EmacsEditor for that. Typing replaces the selected text,
+which can be retrieved with *View.Selection.
+
+DefaultEditor also cuts, copies and pastes the selected text with Ctrl-X,
+Ctrl-C and Ctrl-V, using *View.Clipboard, and reports their errors with
+*View.SetInputError. By default, views use the clipboard of the GUI, which
+sends the text to the terminal using OSC 52, so it works over SSH, and to
+xclip, xsel or wl-copy when they are present. A MemoryClipboard can be used
+instead, for example in tests:
+
+	v.Clipboard = &gocui.MemoryClipboard{}
+
+Editable views keep an undo history of their edit operations, which can be
+reverted with *View.Undo and *View.Redo, bound to Ctrl-Z and Ctrl-Y by
+DefaultEditor. Consecutive typing is undone at once.
+
+EmacsEditor provides Readline/Emacs-style keybindings, with word motion and
+a kill ring:
+
+	v.Editor = &gocui.EmacsEditor{}
+
+VimEditor is a modal editor with vim-style keybindings. Its Mode method
+allows to show the current mode, for instance in a status view.
+
+Setting *View.SingleLine turns an editable view into an input field: Enter
+calls *View.OnSubmit with its text, Esc calls *View.OnCancel, and the arrow
+keys Up and Down browse its History, which can be persisted to a file:
+
+	v.Editable = true
+	v.SingleLine = true
+	v.OnSubmit = submit
+	v.History, err = gocui.LoadHistory(path, 100)
+
+The input of editable views can be restricted and transformed with Filters,
+and validated with a Validator before it is submitted. Errors are drawn on the
+bottom edge of the frame and returned by *View.InputError:
+
+	v.Filters = []gocui.Filter{gocui.DigitsOnly, gocui.MaxLength(5)}
+	v.Validator = validatePort
+
+*View.Placeholder is drawn while the buffer of the view is empty, for example
+to describe the expected input. It is never part of the buffer.
+
+Editable views can complete their text using a Completer. Tab opens a menu
+with the candidates and, if *View.CompletionHint is true, the first one is
+shown as ghost text while typing:
+
+	v.Completer = gocui.CompleterFunc(complete)
+	v.CompletionHint = true
+
+Text pasted in the terminal is delivered at once to the editor of the current
+view, if it is editable, and keybindings are never executed for it. Editors
+can handle it by implementing the Paster interface, otherwise they receive it
+as individual keystrokes. The built-in editors write it literally, including
+tabs, and it is undone at once.
+
+*View.Search highlights the matches of a plain text or regular expression
+pattern and moves the cursor to them, which is continued with
+*View.SearchNext and *View.SearchPrev. A SearchPrompt is a Manager that
+searches incrementally while the pattern is typed:
+
+	search := gocui.NewSearchPrompt("search")
+	g.SetManager(gocui.ManagerFunc(layout), search)
+	g.SetKeybinding("main", '/', gocui.ModNone, search.Open)
+
+The lines of a view can be filtered without modifying its buffer, for example
+to narrow a list while the user types. *View.Line, the cursor and the
+selection keep referring to the original lines, and editors cannot break or
+join lines while some of them are hidden:
+
+	v.FilterLines(query)
+	v.SetLineFilter(func(line string) bool {
+		return strings.Contains(line, "ERROR")
+	})
+
+FilterLines and RankLines match the lines with package fuzzy, which scores
+matches like fzf. RankLines also sorts them by score, which is the base of
+command palettes and file finders. The matched runes are drawn with
+*View.MatchFgColor and *View.MatchBgColor.
+
 Colored text:
 
 Views allow to add colored text using ANSI colors. For example:
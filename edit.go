@@ -13,6 +13,13 @@ type Editor interface {
 	Edit(v *View, key Key, ch rune, mod Modifier)
 }
 
+// Paster is an optional interface that editors can implement to handle the
+// text pasted in the terminal at once. Editors that do not implement it
+// receive the pasted text as individual keystrokes, with tabs as runes.
+type Paster interface {
+	Paste(v *View, text string)
+}
+
 // The EditorFunc type is an adapter to allow the use of ordinary functions as
 // Editors. If f is a function with the appropriate signature, EditorFunc(f)
 // is an Editor object that calls f.
@@ -23,12 +30,47 @@ func (f EditorFunc) Edit(v *View, key Key, ch rune, mod Modifier) {
 	f(v, key, ch, mod)
 }
 
-// DefaultEditor is the default editor.
-var DefaultEditor Editor = EditorFunc(simpleEditor)
+// DefaultEditor is the default editor. Errors of the clipboard operations are
+// reported with View.SetInputError.
+var DefaultEditor Editor = defaultEditor{}
+
+// defaultEditor is the type of DefaultEditor.
+type defaultEditor struct{}
+
+// Edit handles the keystroke for v.
+func (defaultEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	simpleEditor(v, key, ch, mod)
+}
+
+// Paste writes the pasted text at the cursor position, as a single edit
+// operation.
+func (defaultEditor) Paste(v *View, text string) {
+	v.EditWriteString(text)
+}
 
 // simpleEditor is used as the default gocui editor.
 func simpleEditor(v *View, key Key, ch rune, mod Modifier) {
 	switch {
+	case key == KeyArrowLeft && mod == ModCtrl|ModShift:
+		v.ExtendSelectionWord(true)
+	case key == KeyArrowRight && mod == ModCtrl|ModShift:
+		v.ExtendSelectionWord(false)
+	case key == KeyArrowDown && mod == ModShift:
+		v.ExtendSelection(0, 1)
+	case key == KeyArrowUp && mod == ModShift:
+		v.ExtendSelection(0, -1)
+	case key == KeyArrowLeft && mod == ModShift:
+		v.ExtendSelection(-1, 0)
+	case key == KeyArrowRight && mod == ModShift:
+		v.ExtendSelection(1, 0)
+	case key == KeyArrowLeft && mod == ModCtrl:
+		v.ClearSelection()
+		v.MoveCursorWord(true)
+	case key == KeyArrowRight && mod == ModCtrl:
+		v.ClearSelection()
+		v.MoveCursorWord(false)
+	case key == KeyCtrlA:
+		v.SelectAll()
 	case ch != 0 && mod == 0:
 		v.EditWrite(ch)
 	case key == KeySpace:
@@ -42,25 +84,149 @@ func simpleEditor(v *View, key Key, ch rune, mod Modifier) {
 	case key == KeyEnter:
 		v.EditNewLine()
 	case key == KeyArrowDown:
+		v.ClearSelection()
 		v.MoveCursor(0, 1, false)
 	case key == KeyArrowUp:
+		v.ClearSelection()
 		v.MoveCursor(0, -1, false)
 	case key == KeyArrowLeft:
+		v.ClearSelection()
 		v.MoveCursor(-1, 0, false)
 	case key == KeyArrowRight:
+		v.ClearSelection()
 		v.MoveCursor(1, 0, false)
+	case key == KeyCtrlX:
+		if err := v.Cut(); err != nil {
+			v.SetInputError(err)
+		}
+	case key == KeyCtrlC:
+		if err := v.Copy(); err != nil {
+			v.SetInputError(err)
+		}
+	case key == KeyCtrlV:
+		if err := v.Paste(); err != nil {
+			v.SetInputError(err)
+		}
+	case key == KeyCtrlZ:
+		v.Undo()
+	case key == KeyCtrlY:
+		v.Redo()
 	}
 }
 
-// EditWrite writes a rune at the cursor position.
+// EditWrite writes a rune at the cursor position, replacing the selected
+// text, unless it is rejected by the filters of the view.
 func (v *View) EditWrite(ch rune) {
-	v.writeRune(v.cx, v.cy, ch)
+	ch, ok := v.filterWrite(ch)
+	if !ok {
+		return
+	}
+
+	v.beginEdit(editWrite)
+	defer v.endEdit()
+
+	if !v.deleteSelection() {
+		return
+	}
+	if err := v.writeRune(v.cx, v.cy, ch); err != nil {
+		return
+	}
 	v.MoveCursor(1, 0, true)
 }
 
+// EditWriteString writes a string at the cursor position. Line breaks
+// ("\n", "\r\n" or "\r") insert new lines, or spaces in single-line views.
+func (v *View) EditWriteString(s string) {
+	v.beginEdit(editOther)
+	defer v.endEdit()
+
+	var prev rune
+	for _, ch := range s {
+		v.refreshViewLines()
+		switch {
+		case ch == '\n' && prev == '\r':
+		case (ch == '\n' || ch == '\r') && v.SingleLine:
+			v.EditWrite(' ')
+		case ch == '\n' || ch == '\r':
+			v.EditNewLine()
+		default:
+			v.EditWrite(ch)
+		}
+		prev = ch
+	}
+}
+
+// EditDeleteSelection deletes the selected text and moves the cursor to the
+// position where it started, unless it is rejected by the filters of the
+// view.
+func (v *View) EditDeleteSelection() {
+	v.deleteSelection()
+}
+
+// deleteSelection is like EditDeleteSelection, but returns false if the
+// deletion was rejected by the filters of the view, keeping the selection.
+func (v *View) deleteSelection() bool {
+	x0, y0, x1, y1, ok := v.SelectionRange()
+	if ok && (x0 != x1 || y0 != y1) && !v.filterDelete(true) {
+		return false
+	}
+	v.removeSelection()
+	return true
+}
+
+// removeSelection deletes the selected text without passing it through the
+// filters of the view.
+func (v *View) removeSelection() {
+	x0, y0, x1, y1, ok := v.SelectionRange()
+	v.ClearSelection()
+	if !ok || (x0 == x1 && y0 == y1) {
+		return
+	}
+
+	v.deleteText(position{x0, y0}, position{x1, y1})
+}
+
+// editDeleteText is like deleteText, but returns false if the deletion is
+// rejected by the filters of the view.
+func (v *View) editDeleteText(start, end position) bool {
+	if start.before(end) && !v.filterDelete(true) {
+		return false
+	}
+	v.deleteText(start, end)
+	return true
+}
+
+// deleteText deletes the text of the internal buffer between the points
+// start, included, and end, excluded, and moves the cursor to start.
+func (v *View) deleteText(start, end position) {
+	v.beginEdit(editOther)
+	defer v.endEdit()
+
+	v.deleteRange(start, end)
+	v.moveCursorTo(start)
+}
+
 // EditDelete deletes a rune at the cursor position. back determines the
-// direction.
+// direction. If there is selected text, it is deleted instead. The filters of
+// the view can reject it. Lines are not joined if the view has a line filter
+// or query.
 func (v *View) EditDelete(back bool) {
+	if !v.filterDelete(back) {
+		return
+	}
+
+	if back {
+		v.beginEdit(editDeleteBack)
+	} else {
+		v.beginEdit(editDeleteForward)
+	}
+	defer v.endEdit()
+
+	if v.sel.active {
+		v.removeSelection()
+		return
+	}
+
 	x, y := v.ox+v.cx, v.oy+v.cy
 	if y < 0 {
 		return
@@ -84,6 +250,11 @@ func (v *View) EditDelete(back bool) {
 			}
 
 			if v.viewLines[y].linesX == 0 { // regular line
+				if v.filtered() {
+					// the previous line shown may not be the
+					// previous line of the buffer
+					return
+				}
 				v.mergeLines(v.cy - 1)
 				if len(v.viewLines[y-1].line) < maxPrevWidth {
 					v.MoveCursor(-1, 0, true)
@@ -98,6 +269,9 @@ func (v *View) EditDelete(back bool) {
 		}
 	} else {
 		if x == len(v.viewLines[y].line) { // end of the line
+			if v.filtered() {
+				return
+			}
 			v.mergeLines(v.cy)
 		} else { // start/middle of the line
 			v.deleteRune(v.cx, v.cy)
@@ -105,14 +279,112 @@ func (v *View) EditDelete(back bool) {
 	}
 }
 
-// EditNewLine inserts a new line under the cursor.
+// EditNewLine inserts a new line under the cursor, replacing the selected
+// text, unless it is rejected by the filters of the view. Nothing is done if
+// the view has a line filter or query.
 func (v *View) EditNewLine() {
+	if v.filtered() {
+		return
+	}
+	if _, ok := v.filterWrite('\n'); !ok {
+		return
+	}
+
+	v.beginEdit(editOther)
+	defer v.endEdit()
+
+	if !v.deleteSelection() {
+		return
+	}
+
 	v.breakLine(v.cx, v.cy)
 	v.ox = 0
 	v.cx = 0
 	v.MoveCursor(0, 1, true)
 }
 
+// MoveCursorWord moves the cursor to the start of the next word, or the
+// previous one if back is true. Line breaks are word boundaries.
+func (v *View) MoveCursorWord(back bool) {
+	v.moveCursorTo(v.wordBoundary(v.cursorPosition(), back))
+}
+
+// wordBoundary returns the start of the next word of the internal buffer
+// after p, or the previous one if back is true.
+func (v *View) wordBoundary(p position, back bool) position {
+	if p.y >= len(v.lines) {
+		return p
+	}
+	line := v.lines[p.y]
+	x := p.x
+	if back {
+		if x == 0 {
+			if p.y == 0 {
+				return p
+			}
+			return position{len(v.lines[p.y-1]), p.y - 1}
+		}
+		for x > 0 && indexFunc(line[x-1].chr) {
+			x--
+		}
+		for x > 0 && !indexFunc(line[x-1].chr) {
+			x--
+		}
+	} else {
+		if x >= len(line) {
+			if p.y+1 >= len(v.lines) {
+				return p
+			}
+			return position{0, p.y + 1}
+		}
+		for x < len(line) && !indexFunc(line[x].chr) {
+			x++
+		}
+		for x < len(line) && indexFunc(line[x].chr) {
+			x++
+		}
+	}
+	return position{x, p.y}
+}
+
+// moveCursorTo moves the cursor to the point p of the internal buffer,
+// displacing the origin if necessary.
+func (v *View) moveCursorTo(p position) {
+	v.refreshViewLines()
+	maxX, maxY := v.Size()
+
+	vx, vy := p.x, p.y
+	found := false
+	for i, vline := range v.viewLines {
+		if vline.linesY == p.y && p.x >= vline.linesX {
+			vx, vy, found = p.x-vline.linesX, i, true
+		} else if found {
+			break
+		}
+	}
+	if !found && v.filtered() {
+		vx, vy = v.hiddenRow(p)
+	}
+
+	if vy < v.oy {
+		v.oy = vy
+	} else if vy >= v.oy+maxY {
+		v.oy = vy - maxY + 1
+	}
+	v.cy = vy - v.oy
+
+	if v.Wrap {
+		v.cx = vx
+		return
+	}
+	if vx < v.ox {
+		v.ox = vx
+	} else if vx >= v.ox+maxX {
+		v.ox = vx - maxX + 1
+	}
+	v.cx = vx - v.ox
+}
+
 // MoveCursor moves the cursor taking into account the width of the line/view,
 // displacing the origin if necessary.
 func (v *View) MoveCursor(dx, dy int, writeMode bool) {
@@ -209,7 +481,7 @@ func (v *View) MoveCursor(dx, dy int, writeMode bool) {
 		} else {
 			if cx >= maxX {
 				v.ox += cx - maxX + 1
-				v.cx = maxX
+				v.cx = maxX - 1
 			} else {
 				v.cx = cx
 			}
@@ -246,6 +518,12 @@ func (v *View) writeRune(x, y int, ch rune) error {
 		return errors.New("invalid point")
 	}
 
+	y0 := y
+	if y0 > len(v.lines) {
+		y0 = len(v.lines)
+	}
+	before := v.copyLines(y0, y+1)
+
 	if y >= len(v.lines) {
 		s := make([][]cell, y-len(v.lines)+1)
 		v.lines = append(v.lines, s...)
@@ -270,6 +548,7 @@ func (v *View) writeRune(x, y int, ch rune) error {
 		chr:     ch,
 	}
 
+	v.recordChange(y0, before, v.copyLines(y0, y+1))
 	return nil
 }
 
@@ -286,7 +565,9 @@ func (v *View) deleteRune(x, y int) error {
 	if x < 0 || y < 0 || y >= len(v.lines) || x >= len(v.lines[y]) {
 		return errors.New("invalid point")
 	}
+	before := v.copyLines(y, y+1)
 	v.lines[y] = append(v.lines[y][:x], v.lines[y][x+1:]...)
+	v.recordChange(y, before, v.copyLines(y, y+1))
 	return nil
 }
 
@@ -304,8 +585,10 @@ func (v *View) mergeLines(y int) error {
 	}
 
 	if y < len(v.lines)-1 { // otherwise we don't need to merge anything
+		before := v.copyLines(y, y+2)
 		v.lines[y] = append(v.lines[y], v.lines[y+1]...)
 		v.lines = append(v.lines[:y+1], v.lines[y+2:]...)
+		v.recordChange(y, before, v.copyLines(y, y+1))
 	}
 	return nil
 }
@@ -324,6 +607,8 @@ func (v *View) breakLine(x, y int) error {
 		return errors.New("invalid point")
 	}
 
+	before := v.copyLines(y, y+1)
+
 	var left, right []cell
 	if x < len(v.lines[y]) { // break line
 		left = make([]cell, len(v.lines[y][:x]))
@@ -340,5 +625,30 @@ func (v *View) breakLine(x, y int) error {
 	copy(lines, v.lines[:y])
 	copy(lines[y+2:], v.lines[y+1:])
 	v.lines = lines
+
+	v.recordChange(y, before, v.copyLines(y, y+2))
+	return nil
+}
+
+// deleteRange removes the text of the internal buffer between the points
+// start, included, and end, excluded.
+func (v *View) deleteRange(start, end position) error {
+	v.tainted = true
+
+	if end.before(start) || start.y >= len(v.lines) || end.y >= len(v.lines) ||
+		start.x > len(v.lines[start.y]) || end.x > len(v.lines[end.y]) {
+		return errors.New("invalid range")
+	}
+
+	before := v.copyLines(start.y, end.y+1)
+
+	line := make([]cell, start.x, start.x+len(v.lines[end.y])-end.x)
+	copy(line, v.lines[start.y][:start.x])
+	line = append(line, v.lines[end.y][end.x:]...)
+
+	v.lines[start.y] = line
+	v.lines = append(v.lines[:start.y+1], v.lines[end.y+1:]...)
+
+	v.recordChange(start.y, before, v.copyLines(start.y, start.y+1))
 	return nil
 }
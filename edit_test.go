@@ -0,0 +1,111 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPaste(t *testing.T) {
+	editors := map[string]Editor{
+		"default": DefaultEditor,
+		"emacs":   &EmacsEditor{},
+		"func":    EditorFunc(simpleEditor),
+	}
+	complete := CompleterFunc(func(buffer string, cursor int) []Completion {
+		return []Completion{{Text: "candidate"}}
+	})
+
+	for name, editor := range editors {
+		g := &Gui{maxX: 80, maxY: 24}
+		v, _ := g.SetView("main", 0, 0, 40, 10)
+		v.Editable, v.Editor, v.Completer = true, editor, complete
+		if _, err := g.SetCurrentView("main"); err != nil {
+			t.Fatal(err)
+		}
+		v.EditWriteString("x")
+
+		if err := g.onPaste("a\tb\nc"); err != nil {
+			t.Fatal(err)
+		}
+		want := "xa\tb\nc"
+		if got := strings.Join(v.BufferLines(), "\n"); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+		if v.menu != nil {
+			t.Errorf("%s: the completion menu was opened", name)
+		}
+
+		if _, ok := editor.(Paster); ok {
+			v.Undo()
+			if got := strings.Join(v.BufferLines(), "\n"); got != "x" {
+				t.Errorf("%s: after undo, got %q, want %q", name, got, "x")
+			}
+		}
+	}
+}
+
+// noDelete is a Filter that rejects all the deletions.
+type noDelete struct{}
+
+func (noDelete) FilterWrite(v *View, ch rune) (rune, error) { return ch, nil }
+func (noDelete) FilterDelete(v *View, back bool) error      { return errors.New("read only") }
+
+func TestFilterDeleteText(t *testing.T) {
+	tests := []struct {
+		name   string
+		editor Editor
+		edit   func(v *View, e Editor)
+	}{
+		{"write over selection", DefaultEditor, func(v *View, e Editor) {
+			v.SelectAll()
+			e.Edit(v, 0, 'x', ModNone)
+		}},
+		{"cut", DefaultEditor, func(v *View, e Editor) {
+			v.SelectAll()
+			e.Edit(v, KeyCtrlX, 0, ModNone)
+		}},
+		{"paste over selection", DefaultEditor, func(v *View, e Editor) {
+			v.Clipboard.Copy("x")
+			v.SelectAll()
+			e.Edit(v, KeyCtrlV, 0, ModNone)
+		}},
+		{"emacs kill", &EmacsEditor{}, func(v *View, e Editor) {
+			e.Edit(v, KeyCtrlK, 0, ModNone)
+		}},
+		{"emacs transpose", &EmacsEditor{}, func(v *View, e Editor) {
+			e.Edit(v, KeyCtrlT, 0, ModNone)
+		}},
+		{"vim dd", NewVimEditor(), func(v *View, e Editor) {
+			e.Edit(v, 0, 'd', ModNone)
+			e.Edit(v, 0, 'd', ModNone)
+		}},
+		{"vim cw", NewVimEditor(), func(v *View, e Editor) {
+			e.Edit(v, 0, 'c', ModNone)
+			e.Edit(v, 0, 'w', ModNone)
+			e.Edit(v, 0, 'x', ModNone)
+		}},
+	}
+
+	for _, tt := range tests {
+		g := &Gui{maxX: 80, maxY: 24}
+		v, _ := g.SetView("main", 0, 0, 40, 10)
+		v.Editable, v.Editor = true, tt.editor
+		v.Clipboard = &MemoryClipboard{}
+		v.EditWriteString("foo bar")
+		v.moveCursorTo(position{1, 0})
+		v.Filters = []Filter{noDelete{}}
+
+		tt.edit(v, tt.editor)
+		if got := v.Buffer(); got != "foo bar\n" {
+			t.Errorf("%s: got %q, want %q", tt.name, got, "foo bar\n")
+		}
+		if v.InputError() == nil {
+			t.Errorf("%s: got no input error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+// killRingSize is the maximum number of entries of the kill ring.
+const killRingSize = 60
+
+// emacsCommand identifies the last command executed by an EmacsEditor, used
+// to append consecutive kills and to rotate the kill ring after a yank.
+type emacsCommand int
+
+const (
+	emacsOther emacsCommand = iota
+	emacsKill
+	emacsYank
+)
+
+// EmacsEditor is an Editor with Readline/Emacs-style keybindings:
+//
+//	Ctrl-A, Home    beginning of line
+//	Ctrl-E, End     end of line
+//	Ctrl-B, Ctrl-F  backward/forward one character
+//	Alt-B, Alt-F    backward/forward one word
+//	Ctrl-P, Ctrl-N  previous/next line
+//	PgUp, PgDn      previous/next page
+//	Ctrl-D          delete the character under the cursor
+//	Ctrl-K          kill to the end of the line
+//	Ctrl-U          kill to the beginning of the line
+//	Ctrl-W          kill the previous word, or the selected text
+//	Alt-D           kill the next word
+//	Ctrl-Y          yank the last killed text
+//	Alt-Y           replace the yanked text with the previous kill
+//	Ctrl-T          transpose characters
+//	Ctrl-_          undo
+//
+// Consecutive kills are appended to the same entry of the kill ring. Other
+// keys are handled like in DefaultEditor. The zero value is ready to use and
+// the kill ring is shared by all the views using the editor, but kills and
+// yanks are only continued in the view where they started.
+type EmacsEditor struct {
+	ring      []string // kill ring, the most recent kill is the last one
+	view      *View    // view of the last command
+	last      emacsCommand
+	yankStart position // start of the yanked text
+	yankIndex int      // index in ring of the yanked text
+}
+
+// Edit handles the keystroke for v.
+func (e *EmacsEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	last := e.last
+	if v != e.view {
+		last = emacsOther
+	}
+	e.view, e.last = v, emacsOther
+
+	switch {
+	case key == KeyCtrlA || key == KeyHome:
+		v.ClearSelection()
+		p := v.cursorPosition()
+		v.moveCursorTo(position{0, p.y})
+	case key == KeyCtrlE || key == KeyEnd:
+		v.ClearSelection()
+		v.moveCursorTo(v.lineEnd(v.cursorPosition()))
+	case key == KeyCtrlB:
+		v.ClearSelection()
+		v.MoveCursor(-1, 0, false)
+	case key == KeyCtrlF:
+		v.ClearSelection()
+		v.MoveCursor(1, 0, false)
+	case key == KeyCtrlP:
+		v.ClearSelection()
+		v.MoveCursor(0, -1, false)
+	case key == KeyCtrlN:
+		v.ClearSelection()
+		v.MoveCursor(0, 1, false)
+	case mod == ModAlt && ch == 'b':
+		v.ClearSelection()
+		v.MoveCursorWord(true)
+	case mod == ModAlt && ch == 'f':
+		v.ClearSelection()
+		v.MoveCursorWord(false)
+	case key == KeyPgup:
+		v.ClearSelection()
+		v.movePage(-1)
+	case key == KeyPgdn:
+		v.ClearSelection()
+		v.movePage(1)
+	case key == KeyCtrlD:
+		v.EditDelete(false)
+	case key == KeyCtrlK:
+		p := v.cursorPosition()
+		end := v.lineEnd(p)
+		if end == p {
+			// kill the line break
+			end = v.clampPosition(position{0, p.y + 1})
+		}
+		e.kill(v, p, end, last)
+	case key == KeyCtrlU:
+		p := v.cursorPosition()
+		e.kill(v, position{0, p.y}, p, last)
+	case key == KeyCtrlW:
+		if x0, y0, x1, y1, ok := v.SelectionRange(); ok {
+			v.ClearSelection()
+			e.kill(v, position{x0, y0}, position{x1, y1}, last)
+			break
+		}
+		p := v.cursorPosition()
+		e.kill(v, v.wordBoundary(p, true), p, last)
+	case mod == ModAlt && ch == 'd':
+		p := v.cursorPosition()
+		e.kill(v, p, v.wordBoundary(p, false), last)
+	case key == KeyCtrlY:
+		e.yank(v, len(e.ring)-1)
+	case mod == ModAlt && ch == 'y':
+		if last != emacsYank || len(e.ring) == 0 {
+			break
+		}
+		v.beginEdit(editOther)
+		if v.editDeleteText(e.yankStart, v.cursorPosition()) {
+			e.yank(v, (e.yankIndex+len(e.ring)-1)%len(e.ring))
+		}
+		v.endEdit()
+	case key == KeyCtrlT:
+		e.transpose(v)
+	case key == KeyCtrlUnderscore:
+		v.Undo()
+	default:
+		simpleEditor(v, key, ch, mod)
+	}
+}
+
+// Paste writes the pasted text at the cursor position, as a single edit
+// operation.
+func (e *EmacsEditor) Paste(v *View, text string) {
+	e.view, e.last = v, emacsOther
+	v.EditWriteString(text)
+}
+
+// kill deletes the text between start and end, storing it in the kill ring.
+// If the previous command was also a kill, the text is appended to the last
+// entry.
+func (e *EmacsEditor) kill(v *View, start, end position, last emacsCommand) {
+	e.last = emacsKill
+	if !start.before(end) {
+		return
+	}
+
+	text, forward := v.textRange(start, end), start == v.cursorPosition()
+	if !v.editDeleteText(start, end) {
+		return
+	}
+	switch {
+	case last == emacsKill && len(e.ring) > 0 && forward:
+		e.ring[len(e.ring)-1] += text
+	case last == emacsKill && len(e.ring) > 0:
+		e.ring[len(e.ring)-1] = text + e.ring[len(e.ring)-1]
+	default:
+		e.ring = append(e.ring, text)
+		if len(e.ring) > killRingSize {
+			e.ring = e.ring[1:]
+		}
+	}
+}
+
+// yank inserts the entry i of the kill ring at the cursor position.
+func (e *EmacsEditor) yank(v *View, i int) {
+	if i < 0 || i >= len(e.ring) {
+		return
+	}
+	e.last = emacsYank
+
+	v.beginEdit(editOther)
+	defer v.endEdit()
+
+	if !v.deleteSelection() {
+		return
+	}
+	e.yankStart, e.yankIndex = v.cursorPosition(), i
+	v.EditWriteString(e.ring[i])
+}
+
+// transpose swaps the characters before and under the cursor, moving the
+// cursor forward. At the end of the line, it swaps the last two characters.
+func (e *EmacsEditor) transpose(v *View) {
+	p := v.cursorPosition()
+	if p.y >= len(v.lines) {
+		return
+	}
+	line := v.lines[p.y]
+	x := p.x
+	if x >= len(line) {
+		x = len(line) - 1
+	}
+	if x < 1 {
+		return
+	}
+
+	v.beginEdit(editOther)
+	defer v.endEdit()
+
+	a, b := line[x-1].chr, line[x].chr
+	if !v.editDeleteText(position{x - 1, p.y}, position{x + 1, p.y}) {
+		return
+	}
+	v.EditWriteString(string([]rune{b, a}))
+}
+
+// lineEnd returns the end of the line of the internal buffer at p.
+func (v *View) lineEnd(p position) position {
+	if p.y >= len(v.lines) {
+		return p
+	}
+	return position{len(v.lines[p.y]), p.y}
+}
+
+// movePage scrolls the view one page down, or up if dir is negative, moving
+// the cursor the same number of lines.
+func (v *View) movePage(dir int) {
+	_, maxY := v.Size()
+	v.refreshViewLines()
+	if len(v.viewLines) == 0 {
+		return
+	}
+
+	y := v.oy + v.cy + dir*maxY
+	if y < 0 {
+		y = 0
+	} else if y >= len(v.viewLines) {
+		y = len(v.viewLines) - 1
+	}
+	x := v.ox + v.cx
+	vline := v.viewLines[y]
+	if x > len(vline.line) {
+		x = len(vline.line)
+	}
+
+	v.scroll(0, dir*maxY)
+	v.moveCursorTo(position{vline.linesX + x, vline.linesY})
+}
@@ -0,0 +1,193 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+// Filter interface must be satisfied by the input filters of editable views.
+// Filters are called in order before each EditWrite, EditNewLine,
+// EditDelete and EditDeleteSelection. An error rejects the operation and is reported by
+// View.InputError until the next edit operation that the filters accept.
+type Filter interface {
+	// FilterWrite is called before ch is written at the cursor position.
+	// It returns the rune that is written, which allows to transform it.
+	// Line breaks are passed as '\n' and cannot be transformed.
+	FilterWrite(v *View, ch rune) (rune, error)
+
+	// FilterDelete is called before a rune is deleted at the cursor
+	// position. back determines the direction. It is also called, with
+	// back set to true, before deleting the selected text or the text
+	// removed by the commands of the built-in editors, like kills.
+	FilterDelete(v *View, back bool) error
+}
+
+// The WriteFilter type is an adapter to allow the use of ordinary functions
+// as Filters of the written runes. Deletions are always allowed.
+type WriteFilter func(v *View, ch rune) (rune, error)
+
+// FilterWrite calls f(v, ch)
+func (f WriteFilter) FilterWrite(v *View, ch rune) (rune, error) {
+	return f(v, ch)
+}
+
+// FilterDelete returns nil.
+func (f WriteFilter) FilterDelete(v *View, back bool) error {
+	return nil
+}
+
+// DigitsOnly is a Filter that only allows to write decimal digits and line
+// breaks.
+var DigitsOnly Filter = WriteFilter(func(v *View, ch rune) (rune, error) {
+	if (ch < '0' || ch > '9') && ch != '\n' {
+		return ch, errors.New("only digits are allowed")
+	}
+	return ch, nil
+})
+
+// UpperCase is a Filter that converts the written runes to upper case.
+var UpperCase Filter = WriteFilter(func(v *View, ch rune) (rune, error) {
+	return unicode.ToUpper(ch), nil
+})
+
+// MaxLength returns a Filter that limits the length of the text of the view
+// to n runes, including line breaks.
+func MaxLength(n int) Filter {
+	return WriteFilter(func(v *View, ch rune) (rune, error) {
+		if v.writeLength(ch) > n {
+			return ch, fmt.Errorf("maximum length is %d", n)
+		}
+		return ch, nil
+	})
+}
+
+// InputError returns the error of the last filtered edit operation or
+// validation of the view, or nil if they succeeded.
+func (v *View) InputError() error {
+	return v.inputErr
+}
+
+// SetInputError sets the error returned by InputError.
+func (v *View) SetInputError(err error) {
+	v.inputErr, v.filterErr = err, false
+}
+
+// Validate validates the text of the view using its Validator, returning
+// the result, which is also reported by InputError.
+func (v *View) Validate() error {
+	if v.Validator == nil {
+		return nil
+	}
+	v.inputErr, v.filterErr = v.Validator(v.inputText()), false
+	return v.inputErr
+}
+
+// filterWrite passes ch through the filters of the view. ok is false if
+// it was rejected.
+func (v *View) filterWrite(ch rune) (r rune, ok bool) {
+	for _, f := range v.Filters {
+		var err error
+		if ch, err = f.FilterWrite(v, ch); err != nil {
+			v.inputErr, v.filterErr = err, true
+			return ch, false
+		}
+	}
+	v.clearFilterError()
+	return ch, true
+}
+
+// filterDelete passes a deletion through the filters of the view. It
+// returns false if it was rejected.
+func (v *View) filterDelete(back bool) bool {
+	for _, f := range v.Filters {
+		if err := f.FilterDelete(v, back); err != nil {
+			v.inputErr, v.filterErr = err, true
+			return false
+		}
+	}
+	v.clearFilterError()
+	return true
+}
+
+// clearFilterError clears the input error of the view if it was returned by
+// a filter. Errors set by the Validator or SetInputError are kept.
+func (v *View) clearFilterError() {
+	if v.filterErr {
+		v.inputErr, v.filterErr = nil, false
+	}
+}
+
+// textLength returns the length of the text of the view, in runes,
+// excluding the selected text, which is replaced when writing.
+func (v *View) textLength() int {
+	n := 0
+	for i, line := range v.lines {
+		if i > 0 {
+			n++
+		}
+		n += len(line)
+	}
+	if x0, y0, x1, y1, ok := v.SelectionRange(); ok {
+		n -= len([]rune(v.textRange(position{x0, y0}, position{x1, y1})))
+	}
+	return n
+}
+
+// writeLength returns the length of the text of the view, in runes, after
+// writing ch at the cursor position. In overwrite mode, the rune under the
+// cursor is replaced.
+func (v *View) writeLength(ch rune) int {
+	n := v.textLength()
+	if ch != '\n' && v.overwritesRune() {
+		return n
+	}
+	return n + 1
+}
+
+// overwritesRune returns if writing at the cursor position replaces a rune
+// instead of inserting it, once the selected text is deleted.
+func (v *View) overwritesRune() bool {
+	if !v.Overwrite {
+		return false
+	}
+	p := v.cursorPosition()
+	if p.y >= len(v.lines) {
+		return false
+	}
+	n := len(v.lines[p.y])
+	if x0, y0, x1, y1, ok := v.SelectionRange(); ok {
+		p, n = position{x0, y0}, x0+len(v.lines[y1])-x1
+	}
+	// writeRune inserts the runes written on the last rune of a line
+	return p.x < n-1
+}
+
+// drawInputError draws the input error of the view in the bottom edge of
+// its frame.
+func (g *Gui) drawInputError(v *View, bgColor Attribute) error {
+	if v.y1 < 0 || v.y1 >= g.maxY {
+		return nil
+	}
+	fgColor := v.ErrorFgColor
+	if fgColor == ColorDefault {
+		fgColor = ColorRed
+	}
+
+	for i, ch := range v.inputErr.Error() {
+		x := v.x0 + i + 2
+		if x < 0 {
+			continue
+		} else if x > v.x1-2 || x >= g.maxX {
+			break
+		}
+		if err := g.SetRune(x, v.y1, ch, fgColor, bgColor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
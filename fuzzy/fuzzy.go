@@ -0,0 +1,241 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fuzzy implements fuzzy matching of strings, in the style of fzf.
+//
+// A pattern matches a string if the string contains all the runes of the
+// pattern in the same order. Matches are scored so they can be ranked: runes
+// at the start of words and consecutive runes score higher, while gaps
+// between them are penalized. The matching ignores case unless the pattern
+// contains upper case letters.
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scores, after the ones used by fzf.
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	bonusBoundary            = scoreMatch / 2
+	bonusBoundaryWhite       = bonusBoundary + 2
+	bonusBoundaryDelimiter   = bonusBoundary + 1
+	bonusNonWord             = scoreMatch / 2
+	bonusCamel               = bonusBoundary + scoreGapExtension
+	bonusConsecutive         = -(scoreGapStart + scoreGapExtension)
+	bonusFirstCharMultiplier = 2
+
+	noScore = -1 << 30
+)
+
+// Match is a string matched by a pattern.
+type Match struct {
+	// Str is the matched string.
+	Str string
+
+	// Index is the index of Str in the strings passed to Find.
+	Index int
+
+	// Score is the score of the match. The higher, the better.
+	Score int
+
+	// Positions are the indexes of the runes of Str matched by the
+	// pattern, in increasing order.
+	Positions []int
+}
+
+// Find matches pattern against every string of data and returns the matches
+// sorted like Sort. An empty pattern matches all the strings.
+func Find(pattern string, data []string) []Match {
+	var matches []Match
+	for i, str := range data {
+		if m, ok := MatchString(pattern, str); ok {
+			m.Index = i
+			matches = append(matches, m)
+		}
+	}
+	Sort(matches)
+	return matches
+}
+
+// Sort sorts matches by score, from the best one. Ties are broken by length
+// in runes, shorter first, and then by index.
+func Sort(matches []Match) {
+	sort.Slice(matches, func(i, j int) bool {
+		mi, mj := matches[i], matches[j]
+		if mi.Score != mj.Score {
+			return mi.Score > mj.Score
+		}
+		if li, lj := utf8.RuneCountInString(mi.Str), utf8.RuneCountInString(mj.Str); li != lj {
+			return li < lj
+		}
+		return mi.Index < mj.Index
+	})
+}
+
+// MatchString matches pattern against str. ok is false if str does not
+// match. Among all the ways of matching str, m holds the one with the best
+// score.
+func MatchString(pattern, str string) (m Match, ok bool) {
+	m = Match{Str: str}
+	p, s := []rune(pattern), []rune(str)
+	if len(p) == 0 {
+		return m, true
+	}
+
+	text := s
+	if strings.IndexFunc(pattern, unicode.IsUpper) < 0 {
+		text = make([]rune, len(s))
+		for i, r := range s {
+			text[i] = unicode.ToLower(r)
+		}
+	}
+	if !isSubsequence(p, text) {
+		return m, false
+	}
+
+	bonus := make([]int, len(s))
+	prev := classWhite
+	for j, r := range s {
+		c := classOf(r)
+		bonus[j] = bonusFor(prev, c)
+		prev = c
+	}
+
+	// score[i][j] is the best score of p[:i+1] with p[i] matched at
+	// text[j], and from[i][j] is where p[i-1] is matched in that case.
+	score := make([][]int, len(p))
+	from := make([][]int, len(p))
+	for i := range p {
+		score[i] = make([]int, len(text))
+		from[i] = make([]int, len(text))
+
+		// best score of p[:i] matched before j-1, with the penalty of
+		// the gap until j
+		gap, gapFrom := noScore, -1
+		for j := range text {
+			if i > 0 && j >= 2 {
+				if gap != noScore {
+					gap += scoreGapExtension
+				}
+				if sc := score[i-1][j-2]; sc != noScore && sc+scoreGapStart > gap {
+					gap, gapFrom = sc+scoreGapStart, j-2
+				}
+			}
+
+			score[i][j], from[i][j] = noScore, -1
+			if text[j] != p[i] {
+				continue
+			}
+			if i == 0 {
+				score[i][j] = scoreMatch + bonus[j]*bonusFirstCharMultiplier
+				continue
+			}
+			if j > 0 && score[i-1][j-1] != noScore {
+				b := bonus[j]
+				if b < bonusConsecutive {
+					b = bonusConsecutive
+				}
+				score[i][j], from[i][j] = score[i-1][j-1]+scoreMatch+b, j-1
+			}
+			if gap != noScore && gap+scoreMatch+bonus[j] > score[i][j] {
+				score[i][j], from[i][j] = gap+scoreMatch+bonus[j], gapFrom
+			}
+		}
+	}
+
+	last := len(p) - 1
+	end := -1
+	for j, sc := range score[last] {
+		if sc != noScore && (end < 0 || sc > score[last][end]) {
+			end = j
+		}
+	}
+	if end < 0 {
+		return m, false
+	}
+
+	m.Score = score[last][end]
+	m.Positions = make([]int, len(p))
+	for i, j := last, end; i >= 0; i-- {
+		m.Positions[i] = j
+		j = from[i][j]
+	}
+	return m, true
+}
+
+// isSubsequence returns if s contains the runes of p in the same order.
+func isSubsequence(p, s []rune) bool {
+	i := 0
+	for _, r := range s {
+		if i < len(p) && r == p[i] {
+			i++
+		}
+	}
+	return i == len(p)
+}
+
+// charClass is the class of a rune, which determines the bonus of matching
+// it.
+type charClass int
+
+const (
+	classWhite charClass = iota
+	classNonWord
+	classDelimiter
+	classLower
+	classUpper
+	classLetter
+	classNumber
+)
+
+// classOf returns the class of r.
+func classOf(r rune) charClass {
+	switch {
+	case unicode.IsLower(r):
+		return classLower
+	case unicode.IsUpper(r):
+		return classUpper
+	case unicode.IsLetter(r):
+		return classLetter
+	case unicode.IsNumber(r):
+		return classNumber
+	case unicode.IsSpace(r):
+		return classWhite
+	case strings.ContainsRune("/,:;|", r):
+		return classDelimiter
+	}
+	return classNonWord
+}
+
+// bonusFor returns the bonus of matching a rune of class c after a rune of
+// class prev.
+func bonusFor(prev, c charClass) int {
+	if c >= classLower {
+		switch prev {
+		case classWhite:
+			return bonusBoundaryWhite
+		case classDelimiter:
+			return bonusBoundaryDelimiter
+		case classNonWord:
+			return bonusBoundary
+		}
+	}
+	if prev == classLower && c == classUpper || prev != classNumber && c == classNumber {
+		return bonusCamel
+	}
+	switch c {
+	case classNonWord, classDelimiter:
+		return bonusNonWord
+	case classWhite:
+		return bonusBoundaryWhite
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchString(t *testing.T) {
+	tests := []struct {
+		pattern, str string
+		ok           bool
+		score        int
+		positions    []int
+	}{
+		{"", "x", true, 0, nil},
+		{"abc", "abc", true, 76, []int{0, 1, 2}},
+		{"abc", "acb", false, 0, nil},
+		{"ab", "xab", true, 36, []int{1, 2}},
+		{"ab", "aab", true, 49, []int{0, 2}},
+		{"ac", "abc", true, 49, []int{0, 2}},
+
+		// word boundaries
+		{"ab", "a_b", true, 57, []int{0, 2}},
+		{"fb", "foo bar", true, 57, []int{0, 4}},
+		{"fb", "fooBar", true, 55, []int{0, 3}},
+
+		// smart case
+		{"FB", "fooBar", false, 0, nil},
+		{"FB", "FooBar", true, 55, []int{0, 3}},
+
+		// positions are rune indexes
+		{"é", "Écrit", true, 36, []int{0}},
+		{"é", "café", true, 16, []int{3}},
+	}
+
+	for _, tt := range tests {
+		m, ok := MatchString(tt.pattern, tt.str)
+		if ok != tt.ok {
+			t.Errorf("MatchString(%q, %q): got ok %v, want %v", tt.pattern, tt.str, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if m.Score != tt.score || !reflect.DeepEqual(m.Positions, tt.positions) {
+			t.Errorf("MatchString(%q, %q) = score %d, positions %v; want score %d, positions %v",
+				tt.pattern, tt.str, m.Score, m.Positions, tt.score, tt.positions)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	data := []string{"xxab", "éab", "ab", "aab", "éa_b", "ab_", "ba"}
+	// "xxab" and "éab" have the same score and number of bytes, but "éab"
+	// has fewer runes.
+	want := []int{2, 5, 3, 4, 1, 0}
+
+	matches := Find("ab", data)
+	var got []int
+	for _, m := range matches {
+		if m.Str != data[m.Index] {
+			t.Errorf("match %d: got Str %q, want %q", m.Index, m.Str, data[m.Index])
+		}
+		got = append(got, m.Index)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got indexes %v, want %v", got, want)
+	}
+}
@@ -6,6 +6,11 @@ package gocui
 
 import (
 	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/nsf/termbox-go"
 )
@@ -16,6 +21,17 @@ var (
 
 	// ErrUnknownView allows to assert if a View must be initialized.
 	ErrUnknownView = errors.New("unknown view")
+
+	// ErrNotHandled can be returned by keybinding handlers to indicate that
+	// the event was not consumed, so it must be passed to the next matching
+	// keybinding or, if there is none, to the editor of the current view.
+	ErrNotHandled = errors.New("not handled")
+
+	// ErrPassToEditor can be returned by keybinding handlers to pass the
+	// event directly to the editor of the current view, skipping the rest
+	// of matching keybindings. For instance, it allows a text input to type
+	// a key that has a global keybinding.
+	ErrPassToEditor = errors.New("pass to editor")
 )
 
 // OutputMode represents the terminal's output mode (8 or 256 colors).
@@ -32,14 +48,25 @@ const (
 // Gui represents the whole User Interface, including the views, layouts
 // and keybindings.
 type Gui struct {
-	tbEvents    chan termbox.Event
+	tbEvents    chan inputEvent
 	userEvents  chan userEvent
 	views       []*View
 	currentView *View
 	managers    []Manager
 	keybindings []*keybinding
+	actions     map[string]func(*Gui, *View) error
+	keymap      string
 	maxX, maxY  int
 	outputMode  OutputMode
+	tty         io.Writer // used to send control sequences to the terminal
+
+	mouseButton Key   // button being pressed, 0 if none
+	mouseView   *View // view that receives the events of the pressed button
+	lastClick   clickState
+	hoverView   *View // view under the pointer
+	viewDrag    *viewDrag
+	selectView  *View     // view whose text is being selected with the mouse
+	selectInit  selection // selection made by the press that started it
 
 	// BgColor and FgColor allow to configure the background and foreground
 	// colors of the GUI.
@@ -49,6 +76,10 @@ type Gui struct {
 	// foreground colors of the frame of the current view.
 	SelBgColor, SelFgColor Attribute
 
+	// Clipboard is the default clipboard of the views. It is a
+	// SystemClipboard by default.
+	Clipboard Clipboard
+
 	// If Highlight is true, Sel{Bg,Fg}Colors will be used to draw the
 	// frame of the current view.
 	Highlight bool
@@ -59,6 +90,21 @@ type Gui struct {
 	// If Mouse is true then mouse events will be enabled.
 	Mouse bool
 
+	// If MouseMotion is true, the terminal reports the motion of the pointer
+	// even when no button is pressed. It is required by View.OnMouseEnter,
+	// View.OnMouseLeave and View.OnMouseMove. Mouse must be true too.
+	MouseMotion bool
+
+	// ClickMode configures what happens when a mouse button is pressed over
+	// the content of a view, in addition to executing the bindings.
+	// NewGui sets it to ClickCursor.
+	ClickMode ClickMode
+
+	// MultiClickInterval is the maximum time between the presses of a
+	// double or triple click (see MouseEvent.Clicks). If it is 0, every
+	// press is reported as a single click.
+	MultiClickInterval time.Duration
+
 	// If InputEsc is true, when ESC sequence is in the buffer and it doesn't
 	// match any known sequence, ESC means KeyEsc.
 	InputEsc bool
@@ -79,13 +125,22 @@ func NewGui(mode OutputMode) (*Gui, error) {
 	g.outputMode = mode
 	termbox.SetOutputMode(termbox.OutputMode(mode))
 
-	g.tbEvents = make(chan termbox.Event, 20)
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		g.tty = tty
+	} else {
+		g.tty = os.Stdout
+	}
+
+	g.tbEvents = make(chan inputEvent, 20)
 	g.userEvents = make(chan userEvent, 20)
 
 	g.maxX, g.maxY = termbox.Size()
 
 	g.BgColor, g.FgColor = ColorDefault, ColorDefault
 	g.SelBgColor, g.SelFgColor = ColorDefault, ColorDefault
+	g.MultiClickInterval = 400 * time.Millisecond
+	g.ClickMode = ClickCursor
+	g.Clipboard = NewSystemClipboard(g.tty)
 
 	return g, nil
 }
@@ -93,7 +148,34 @@ func NewGui(mode OutputMode) (*Gui, error) {
 // Close finalizes the library. It should be called after a successful
 // initialization and when gocui is not needed anymore.
 func (g *Gui) Close() {
+	g.setTerminalModes(false)
 	termbox.Close()
+	if tty, ok := g.tty.(*os.File); ok && tty != os.Stdout {
+		tty.Close()
+	}
+}
+
+// setTerminalModes enables or disables the terminal modes needed by gocui
+// that are not handled by termbox. Terminals that do not support them
+// ignore the control sequences.
+func (g *Gui) setTerminalModes(enable bool) {
+	if g.tty == nil {
+		return
+	}
+	if enable {
+		// report modified keys like Ctrl+Enter (xterm modifyOtherKeys)
+		io.WriteString(g.tty, "\x1b[>4;1m")
+		// bracketed paste
+		io.WriteString(g.tty, "\x1b[?2004h")
+		if g.Mouse && g.MouseMotion {
+			// any-motion mouse tracking
+			io.WriteString(g.tty, "\x1b[?1003h")
+		}
+	} else {
+		io.WriteString(g.tty, "\x1b[>4m")
+		io.WriteString(g.tty, "\x1b[?2004l")
+		io.WriteString(g.tty, "\x1b[?1003l")
+	}
 }
 
 // Size returns the terminal's size.
@@ -147,6 +229,7 @@ func (g *Gui) SetView(name string, x0, y0, x1, y1 int) (*View, error) {
 	v := newView(name, x0, y0, x1, y1, g.outputMode)
 	v.BgColor, v.FgColor = g.BgColor, g.FgColor
 	v.SelBgColor, v.SelFgColor = g.SelBgColor, g.SelFgColor
+	v.Clipboard = g.Clipboard
 	g.views = append(g.views, v)
 	return v, ErrUnknownView
 }
@@ -192,16 +275,25 @@ func (g *Gui) View(name string) (*View, error) {
 }
 
 // ViewByPosition returns a pointer to a view matching the given position, or
-// error ErrUnknownView if a view in that position does not exist.
+// error ErrUnknownView if a view in that position does not exist. The frame
+// of the view, drawn or not, is part of it.
 func (g *Gui) ViewByPosition(x, y int) (*View, error) {
+	v, _, err := g.ViewAreaByPosition(x, y)
+	return v, err
+}
+
+// ViewAreaByPosition returns a pointer to a view matching the given position
+// and the area of the view where the position is, or error ErrUnknownView if a
+// view in that position does not exist.
+func (g *Gui) ViewAreaByPosition(x, y int) (*View, ViewArea, error) {
 	// traverse views in reverse order checking top views first
 	for i := len(g.views); i > 0; i-- {
 		v := g.views[i-1]
-		if x > v.x0 && x < v.x1 && y > v.y0 && y < v.y1 {
-			return v, nil
+		if x >= v.x0 && x <= v.x1 && y >= v.y0 && y <= v.y1 {
+			return v, v.area(x, y), nil
 		}
 	}
-	return nil, ErrUnknownView
+	return nil, AreaNone, ErrUnknownView
 }
 
 // ViewPosition returns the coordinates of the view with the given name, or
@@ -219,6 +311,18 @@ func (g *Gui) ViewPosition(name string) (x0, y0, x1, y1 int, err error) {
 func (g *Gui) DeleteView(name string) error {
 	for i, v := range g.views {
 		if v.name == name {
+			if v == g.mouseView {
+				g.mouseView = nil
+			}
+			if v == g.hoverView {
+				g.hoverView = nil
+			}
+			if g.viewDrag != nil && v == g.viewDrag.v {
+				g.viewDrag = nil
+			}
+			if v == g.selectView {
+				g.selectView = nil
+			}
 			g.views = append(g.views[:i], g.views[i+1:]...)
 			return nil
 		}
@@ -244,11 +348,23 @@ func (g *Gui) CurrentView() *View {
 }
 
 // SetKeybinding creates a new keybinding. If viewname equals to ""
-// (empty string) then the keybinding will apply to all views. key must
-// be a rune or a Key.
+// (empty string) then the keybinding will apply to all views. viewname can
+// also be a pattern, with the syntax of path.Match, like "list-*". key must
+// be a rune or a Key. View names are always matched exactly too, and
+// malformed patterns are reported with path.ErrBadPattern.
+//
+// Only one handler consumes each event. When several keybindings match,
+// they are tried by priority (see SetKeybindingPriority), view-specific
+// keybindings before global ones, and then in registration order, until one
+// of them returns something different from ErrNotHandled. ErrPassToEditor
+// stops the search and passes the event to the editor of the current view.
+// The keybindings after the one consuming the event are not executed.
 func (g *Gui) SetKeybinding(viewname string, key interface{}, mod Modifier, handler func(*Gui, *View) error) error {
 	var kb *keybinding
 
+	if err := checkViewName(viewname); err != nil {
+		return err
+	}
 	k, ch, err := getKey(key)
 	if err != nil {
 		return err
@@ -258,6 +374,25 @@ func (g *Gui) SetKeybinding(viewname string, key interface{}, mod Modifier, hand
 	return nil
 }
 
+// SetKeybindingFunc creates a new keybinding that applies to the views for
+// which match returns true. scope names the keybinding, so it can be
+// referenced later in DeleteKeybinding, DeleteKeybindings and friends,
+// and reported by Keybindings. key must be a rune or a Key.
+func (g *Gui) SetKeybindingFunc(scope string, match func(*View) bool, key interface{}, mod Modifier, handler func(*Gui, *View) error) error {
+	if match == nil {
+		return errors.New("invalid match function")
+	}
+
+	k, ch, err := getKey(key)
+	if err != nil {
+		return err
+	}
+	kb := newKeybinding(scope, k, ch, mod, handler)
+	kb.match = match
+	g.keybindings = append(g.keybindings, kb)
+	return nil
+}
+
 // DeleteKeybinding deletes a keybinding.
 func (g *Gui) DeleteKeybinding(viewname string, key interface{}, mod Modifier) error {
 	k, ch, err := getKey(key)
@@ -274,6 +409,67 @@ func (g *Gui) DeleteKeybinding(viewname string, key interface{}, mod Modifier) e
 	return errors.New("keybinding not found")
 }
 
+// SetKeybindingPriority sets the priority of the keybindings matching the
+// given view, key and modifier. Keybindings with higher priority are executed
+// first. The default priority is 0.
+func (g *Gui) SetKeybindingPriority(viewname string, key interface{}, mod Modifier, priority int) error {
+	k, ch, err := getKey(key)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, kb := range g.keybindings {
+		if kb.viewName == viewname && kb.ch == ch && kb.key == k && kb.mod == mod {
+			kb.priority = priority
+			found = true
+		}
+	}
+	if !found {
+		return errors.New("keybinding not found")
+	}
+	return nil
+}
+
+// DescribeKeybinding sets the category and description of the keybindings
+// matching the given view, key and modifier. They are reported by
+// Keybindings and shown by HelpOverlay.
+func (g *Gui) DescribeKeybinding(viewname string, key interface{}, mod Modifier, category, description string) error {
+	k, ch, err := getKey(key)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, kb := range g.keybindings {
+		if kb.viewName == viewname && kb.ch == ch && kb.key == k && kb.mod == mod {
+			kb.category = category
+			kb.description = description
+			found = true
+		}
+	}
+	if !found {
+		return errors.New("keybinding not found")
+	}
+	return nil
+}
+
+// Keybindings returns the keybindings that are active for the given view in
+// the current keymap, including the global ones. If v is nil, only the global
+// keybindings are returned.
+func (g *Gui) Keybindings(v *View) []Keybinding {
+	var kbs []Keybinding
+	for _, kb := range g.keybindings {
+		if (kb.handler == nil && kb.mouseHandler == nil) || !kb.matchKeymap(g.keymap) {
+			continue
+		}
+		if kb.isGlobal() || (v != nil && kb.matchView(v)) {
+			kbs = append(kbs, kb.export())
+		}
+	}
+	return kbs
+}
+
 // DeleteKeybindings deletes all keybindings of view.
 func (g *Gui) DeleteKeybindings(viewname string) {
 	var s []*keybinding
@@ -336,8 +532,12 @@ func (g *Gui) SetManager(managers ...Manager) {
 	g.currentView = nil
 	g.views = nil
 	g.keybindings = nil
+	g.mouseButton, g.mouseView = 0, nil
+	g.hoverView = nil
+	g.viewDrag = nil
+	g.selectView = nil
 
-	go func() { g.tbEvents <- termbox.Event{Type: termbox.EventResize} }()
+	go func() { g.tbEvents <- inputEvent{Event: termbox.Event{Type: termbox.EventResize}} }()
 }
 
 // SetManagerFunc sets the given manager function. It deletes all views and
@@ -349,11 +549,7 @@ func (g *Gui) SetManagerFunc(manager func(*Gui) error) {
 // MainLoop runs the main loop until an error is returned. A successful
 // finish should return ErrQuit.
 func (g *Gui) MainLoop() error {
-	go func() {
-		for {
-			g.tbEvents <- termbox.PollEvent()
-		}
-	}()
+	go g.pollInput()
 
 	inputMode := termbox.InputAlt
 	if g.InputEsc {
@@ -363,6 +559,7 @@ func (g *Gui) MainLoop() error {
 		inputMode |= termbox.InputMouse
 	}
 	termbox.SetInputMode(inputMode)
+	g.setTerminalModes(true)
 
 	if err := g.flush(); err != nil {
 		return err
@@ -407,10 +604,13 @@ func (g *Gui) consumeevents() error {
 
 // handleEvent handles an event, based on its type (key-press, error,
 // etc.)
-func (g *Gui) handleEvent(ev *termbox.Event) error {
+func (g *Gui) handleEvent(ev *inputEvent) error {
+	if ev.paste {
+		return g.onPaste(ev.text)
+	}
 	switch ev.Type {
 	case termbox.EventKey, termbox.EventMouse:
-		return g.onKey(ev)
+		return g.onKey(&ev.Event)
 	case termbox.EventError:
 		return ev.Err
 	default:
@@ -445,6 +645,12 @@ func (g *Gui) flush() error {
 			} else {
 				fgColor = g.FgColor
 				bgColor = g.BgColor
+				if v.FrameFgColor != ColorDefault {
+					fgColor = v.FrameFgColor
+				}
+				if v.FrameBgColor != ColorDefault {
+					bgColor = v.FrameBgColor
+				}
 			}
 
 			if err := g.drawFrameEdges(v, fgColor, bgColor); err != nil {
@@ -458,11 +664,21 @@ func (g *Gui) flush() error {
 					return err
 				}
 			}
+			if v.inputErr != nil {
+				if err := g.drawInputError(v, bgColor); err != nil {
+					return err
+				}
+			}
 		}
 		if err := g.draw(v); err != nil {
 			return err
 		}
 	}
+	if v := g.currentView; v != nil && v.menu != nil {
+		if err := g.drawCompletionMenu(v); err != nil {
+			return err
+		}
+	}
 	termbox.Flush()
 	return nil
 }
@@ -597,40 +813,97 @@ func (g *Gui) onKey(ev *termbox.Event) error {
 		if matched {
 			break
 		}
-		if g.currentView != nil && g.currentView.Editable && g.currentView.Editor != nil {
-			g.currentView.Editor.Edit(g.currentView, Key(ev.Key), ev.Ch, Modifier(ev.Mod))
+		if v := g.currentView; v != nil && v.Editable && v.Editor != nil {
+			return g.edit(v, Key(ev.Key), ev.Ch, Modifier(ev.Mod))
 		}
 	case termbox.EventMouse:
-		mx, my := ev.MouseX, ev.MouseY
-		v, err := g.ViewByPosition(mx, my)
-		if err != nil {
-			break
+		return g.onMouse(ev)
+	}
+
+	return nil
+}
+
+// edit passes a keystroke to the editable view v, handling completion and
+// the single-line mode.
+func (g *Gui) edit(v *View, key Key, ch rune, mod Modifier) error {
+	if v.Completer != nil {
+		if v.completeKey(key, mod) {
+			return nil
 		}
-		if err := v.SetCursor(mx-v.x0-1, my-v.y0-1); err != nil {
-			return err
+		defer v.updateHint()
+	}
+	if v.SingleLine {
+		return g.editSingleLine(v, key, ch, mod)
+	}
+	v.Editor.Edit(v, key, ch, mod)
+	return nil
+}
+
+// onPaste delivers the text pasted in the terminal to the editor of the
+// current view. Keybindings are never executed for pasted text, which is
+// discarded if the current view is not editable.
+func (g *Gui) onPaste(text string) error {
+	v := g.currentView
+	if v == nil || !v.Editable || v.Editor == nil {
+		return nil
+	}
+	if v.SingleLine {
+		text = strings.Replace(text, "\n", " ", -1)
+	}
+	if p, ok := v.Editor.(Paster); ok {
+		p.Paste(v, text)
+		return nil
+	}
+	for _, ch := range text {
+		var key Key
+		switch {
+		case ch == '\n':
+			key, ch = KeyEnter, 0
+		case ch == '\t':
+			// passed as a rune, since the Tab key completes text
+		case ch == ' ':
+			key, ch = KeySpace, 0
+		case ch < ' ' || ch == '\x7f':
+			// other control characters would look like keybindings
+			continue
 		}
-		if _, err := g.execKeybindings(v, ev); err != nil {
+		v.refreshViewLines()
+		if err := g.edit(v, key, ch, ModNone); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
 
 // execKeybindings executes the keybinding handlers that match the passed view
-// and event. The value of matched is true if there is a match and no errors.
+// and event, in order of precedence, until one of them consumes the event or
+// returns ErrPassToEditor. The value of matched is true if the event was
+// consumed and no errors.
 func (g *Gui) execKeybindings(v *View, ev *termbox.Event) (matched bool, err error) {
-	matched = false
+	var kbs []*keybinding
 	for _, kb := range g.keybindings {
 		if kb.handler == nil {
 			continue
 		}
-		if kb.matchKeypress(Key(ev.Key), ev.Ch, Modifier(ev.Mod)) && kb.matchView(v) {
-			if err := kb.handler(g, v); err != nil {
-				return false, err
+		if kb.matchKeypress(Key(ev.Key), ev.Ch, Modifier(ev.Mod)) && kb.matchView(v) && kb.matchKeymap(g.keymap) {
+			kbs = append(kbs, kb)
+		}
+	}
+	sort.SliceStable(kbs, func(i, j int) bool {
+		return kbs[i].precedes(kbs[j])
+	})
+
+	for _, kb := range kbs {
+		if err := kb.handler(g, v); err != nil {
+			if err == ErrNotHandled {
+				continue
+			}
+			if err == ErrPassToEditor {
+				return false, nil
 			}
-			matched = true
+			return false, err
 		}
+		return true, nil
 	}
-	return matched, nil
+	return false, nil
 }
@@ -0,0 +1,145 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HelpOverlay is a Manager that shows a view listing the keybindings that are
+// active for the focused view, including the global ones. Keybindings without
+// a description are not listed. It should be the last manager passed to
+// SetManager, so it is drawn on top of other views.
+type HelpOverlay struct {
+	name    string
+	visible bool
+
+	// Title is the title of the help view.
+	Title string
+}
+
+// NewHelpOverlay returns a new HelpOverlay that uses a view with the given
+// name. It is hidden by default.
+func NewHelpOverlay(name string) *HelpOverlay {
+	return &HelpOverlay{name: name, Title: "Help"}
+}
+
+// Visible returns if the help view is shown.
+func (h *HelpOverlay) Visible() bool {
+	return h.visible
+}
+
+// Toggle shows or hides the help view. It can be used directly as a
+// keybinding handler.
+func (h *HelpOverlay) Toggle(g *Gui, v *View) error {
+	h.visible = !h.visible
+	if !h.visible {
+		if err := g.DeleteView(h.name); err != nil && err != ErrUnknownView {
+			return err
+		}
+	}
+	return nil
+}
+
+// Layout draws the help view if it is visible.
+func (h *HelpOverlay) Layout(g *Gui) error {
+	if !h.visible {
+		return nil
+	}
+
+	lines := helpLines(g.Keybindings(g.CurrentView()))
+
+	w := len(h.Title) + 4
+	for _, l := range lines {
+		if n := len([]rune(l)); n+1 > w {
+			w = n + 1
+		}
+	}
+	ht := len(lines) + 1
+
+	maxX, maxY := g.Size()
+	if w > maxX-1 {
+		w = maxX - 1
+	}
+	if ht > maxY-1 {
+		ht = maxY - 1
+	}
+	if w < 1 || ht < 1 {
+		// no room for the overlay
+		if err := g.DeleteView(h.name); err != nil && err != ErrUnknownView {
+			return err
+		}
+		return nil
+	}
+	x0, y0 := (maxX-w)/2, (maxY-ht)/2
+
+	v, err := g.SetView(h.name, x0, y0, x0+w, y0+ht)
+	if err != nil && err != ErrUnknownView {
+		return err
+	}
+	v.Title = h.Title
+	v.Clear()
+	for _, l := range lines {
+		fmt.Fprintln(v, l)
+	}
+
+	_, err = g.SetViewOnTop(h.name)
+	return err
+}
+
+// helpLines returns the lines of the help view, grouping the keybindings with
+// a description by category.
+func helpLines(all []Keybinding) []string {
+	var kbs []Keybinding
+	for _, kb := range all {
+		if kb.Description != "" {
+			kbs = append(kbs, kb)
+		}
+	}
+
+	var categories []string
+	groups := make(map[string][]Keybinding)
+	for _, kb := range kbs {
+		if _, ok := groups[kb.Category]; !ok {
+			categories = append(categories, kb.Category)
+		}
+		groups[kb.Category] = append(groups[kb.Category], kb)
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		// keybindings without category go last
+		if categories[i] == "" || categories[j] == "" {
+			return categories[j] == ""
+		}
+		return categories[i] < categories[j]
+	})
+
+	keyWidth := 0
+	for _, kb := range kbs {
+		if n := len([]rune(kb.KeyString())); n > keyWidth {
+			keyWidth = n
+		}
+	}
+
+	var lines []string
+	for i, c := range categories {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		if c != "" {
+			lines = append(lines, c)
+		} else if len(categories) > 1 {
+			lines = append(lines, "Other")
+		}
+		for _, kb := range groups[c] {
+			k := kb.KeyString()
+			pad := strings.Repeat(" ", keyWidth-len([]rune(k)))
+			l := fmt.Sprintf("  %s%s  %s", k, pad, kb.Description)
+			lines = append(lines, strings.TrimRight(l, " "))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,147 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// History keeps the entries submitted in a single-line view, which can be
+// browsed with the arrow keys. It can be persisted to a file.
+type History struct {
+	entries []string
+	pos     int    // entry being browsed, len(entries) if none
+	draft   string // text being edited when browsing started
+	path    string
+	max     int
+}
+
+// NewHistory returns a new History that keeps at most max entries. If max
+// is 0, there is no limit.
+func NewHistory(max int) *History {
+	return &History{max: max}
+}
+
+// LoadHistory returns a new History that keeps at most max entries and is
+// persisted to the file at path. If the file exists, the entries are loaded
+// from it. If max is 0, there is no limit.
+func LoadHistory(path string, max int) (*History, error) {
+	h := &History{path: path, max: max}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		h.entries = append(h.entries, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if h.max > 0 && len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+		if err := h.save(); err != nil {
+			return nil, err
+		}
+	}
+	h.pos = len(h.entries)
+	return h, nil
+}
+
+// Entries returns the entries of the history, from the oldest to the
+// newest.
+func (h *History) Entries() []string {
+	return append([]string(nil), h.entries...)
+}
+
+// Add appends an entry to the history, unless it is empty or equal to the
+// last one, and stops browsing it. If the history is persisted, the entry
+// is appended to its file, which is rewritten when old entries are dropped.
+func (h *History) Add(entry string) error {
+	h.Reset()
+	if strings.TrimSpace(entry) == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return nil
+	}
+
+	h.entries = append(h.entries, entry)
+	trimmed := h.max > 0 && len(h.entries) > h.max
+	if trimmed {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+	h.pos = len(h.entries)
+
+	if h.path == "" {
+		return nil
+	}
+	if trimmed {
+		return h.save()
+	}
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(entry + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Prev returns the entry previous to the one being browsed. current is the
+// text being edited, which is returned by Next at the end of the history.
+// ok is false if there are no previous entries.
+func (h *History) Prev(current string) (entry string, ok bool) {
+	if h.pos == 0 {
+		return "", false
+	}
+	if h.pos == len(h.entries) {
+		h.draft = current
+	}
+	h.pos--
+	return h.entries[h.pos], true
+}
+
+// Next returns the entry next to the one being browsed. ok is false if the
+// history is not being browsed.
+func (h *History) Next() (entry string, ok bool) {
+	if h.pos >= len(h.entries) {
+		return "", false
+	}
+	h.pos++
+	if h.pos == len(h.entries) {
+		return h.draft, true
+	}
+	return h.entries[h.pos], true
+}
+
+// Reset stops browsing the history.
+func (h *History) Reset() {
+	h.pos, h.draft = len(h.entries), ""
+}
+
+// save writes all the entries to the file of the history.
+func (h *History) save() error {
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, e := range h.entries {
+		w.WriteString(e + "\n")
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,183 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHistoryBrowse(t *testing.T) {
+	h := NewHistory(0)
+	for _, e := range []string{"one", "two", "two", "", "  ", "three"} {
+		if err := h.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := h.Entries(), []string{"one", "two", "three"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got entries %q, want %q", got, want)
+	}
+
+	steps := []struct {
+		prev  bool
+		entry string
+		ok    bool
+	}{
+		{false, "", false},
+		{true, "three", true},
+		{true, "two", true},
+		{true, "one", true},
+		{true, "", false},
+		{false, "two", true},
+		{false, "three", true},
+		{false, "draft", true},
+		{false, "", false},
+	}
+	for i, s := range steps {
+		var (
+			entry string
+			ok    bool
+		)
+		if s.prev {
+			entry, ok = h.Prev("draft")
+		} else {
+			entry, ok = h.Next()
+		}
+		if entry != s.entry || ok != s.ok {
+			t.Errorf("step %d: got %q, %v; want %q, %v", i, entry, ok, s.entry, s.ok)
+		}
+	}
+
+	// Add stops browsing
+	h.Prev("draft")
+	h.Add("four")
+	if entry, ok := h.Next(); ok {
+		t.Errorf("after Add: Next returned %q", entry)
+	}
+	if entry, _ := h.Prev(""); entry != "four" {
+		t.Errorf("after Add: Prev returned %q, want %q", entry, "four")
+	}
+}
+
+func TestHistoryMax(t *testing.T) {
+	h := NewHistory(2)
+	for _, e := range []string{"one", "two", "three"} {
+		h.Add(e)
+	}
+	if got, want := h.Entries(), []string{"two", "three"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got entries %q, want %q", got, want)
+	}
+}
+
+func TestHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	readFile := func() string {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	// missing file
+	h, err := LoadHistory(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Entries()) != 0 {
+		t.Fatalf("got entries %q, want none", h.Entries())
+	}
+
+	// entries are appended
+	for _, e := range []string{"one", "two", "two", "three"} {
+		if err := h.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := readFile(), "one\ntwo\nthree\n"; got != want {
+		t.Errorf("after Add: got file %q, want %q", got, want)
+	}
+
+	// the file is rewritten when entries are dropped
+	if err := h.Add("four"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readFile(), "two\nthree\nfour\n"; got != want {
+		t.Errorf("after trimming: got file %q, want %q", got, want)
+	}
+
+	h, err = LoadHistory(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.Entries(), []string{"two", "three", "four"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after loading: got entries %q, want %q", got, want)
+	}
+	if entry, _ := h.Prev(""); entry != "four" {
+		t.Errorf("after loading: Prev returned %q, want %q", entry, "four")
+	}
+
+	// loading with a lower maximum trims the file
+	if _, err := LoadHistory(path, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readFile(), "four\n"; got != want {
+		t.Errorf("after loading with max 1: got file %q, want %q", got, want)
+	}
+}
+
+func TestSingleLineHistory(t *testing.T) {
+	g := &Gui{maxX: 80, maxY: 24}
+	v, _ := g.SetView("input", 0, 0, 10, 2)
+	v.Editable, v.SingleLine, v.Wrap = true, true, true
+	v.History = NewHistory(0)
+	var submitted []string
+	v.OnSubmit = func(g *Gui, v *View, text string) error {
+		submitted = append(submitted, text)
+		v.Clear()
+		v.SetOrigin(0, 0)
+		return v.SetCursor(0, 0)
+	}
+
+	long := strings.Repeat("x", 20)
+	for _, s := range []string{"first", long} {
+		for _, ch := range s {
+			g.edit(v, 0, ch, ModNone)
+		}
+		g.edit(v, KeyEnter, 0, ModNone)
+	}
+	if !reflect.DeepEqual(submitted, []string{"first", long}) {
+		t.Fatalf("got submitted %q", submitted)
+	}
+	if v.Wrap {
+		t.Error("Wrap was not cleared")
+	}
+
+	for _, ch := range "draft" {
+		g.edit(v, 0, ch, ModNone)
+	}
+	steps := []struct {
+		key  Key
+		want string
+	}{
+		{KeyArrowUp, long},
+		{KeyArrowUp, "first"},
+		{KeyArrowUp, "first"},
+		{KeyArrowDown, long},
+		{KeyArrowDown, "draft"},
+	}
+	for i, s := range steps {
+		g.edit(v, s.key, 0, ModNone)
+		if got := v.inputText(); got != s.want {
+			t.Errorf("step %d: got %q, want %q", i, got, s.want)
+		}
+		if len(v.viewLines) > 1 {
+			t.Errorf("step %d: got %d view lines, want 1", i, len(v.viewLines))
+		}
+	}
+}
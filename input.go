@@ -0,0 +1,483 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nsf/termbox-go"
+)
+
+// escapeDelay is the time to wait for the rest of an escape sequence before
+// taking its first bytes as Esc or Alt keys, like termbox does.
+const escapeDelay = 100 * time.Millisecond
+
+// pollInput reads the input of the terminal, decodes it and sends the
+// resulting events to the GUI.
+func (g *Gui) pollInput() {
+	raw := make(chan []byte)
+	go func() {
+		for {
+			data := make([]byte, 256)
+			ev := termbox.PollRawEvent(data)
+			if ev.Type != termbox.EventRaw {
+				g.tbEvents <- inputEvent{Event: ev}
+				continue
+			}
+			raw <- data[:ev.N]
+		}
+	}()
+
+	var (
+		dec     inputDecoder
+		timeout <-chan time.Time
+	)
+	for {
+		final := false
+		select {
+		case data := <-raw:
+			dec.feed(data)
+		case <-timeout:
+			final = true
+		}
+		for {
+			ev, ok := dec.next(final)
+			if !ok {
+				break
+			}
+			g.tbEvents <- ev
+		}
+		timeout = nil
+		if !final && dec.incomplete() {
+			timeout = time.After(escapeDelay)
+		}
+	}
+}
+
+// inputDecoder turns the raw input read from the terminal into events. It
+// decodes the escape sequences that termbox does not understand, like the
+// xterm modified-key sequences, and delegates the rest to termbox.
+type inputDecoder struct {
+	buf     []byte
+	pasting bool // the start of the pasted text was already delivered
+}
+
+// feed appends raw input to the decoder.
+func (d *inputDecoder) feed(data []byte) {
+	d.buf = append(d.buf, data...)
+}
+
+// incomplete returns if the input left ends with the start of an escape
+// sequence or is pasted text without its end, which may be completed by the
+// next read.
+func (d *inputDecoder) incomplete() bool {
+	return d.pasting || bytes.HasPrefix(d.buf, []byte(pasteStart)) || escapePrefix(d.buf)
+}
+
+// next returns the next event in the input. ok is false if there are no
+// complete events left.
+//
+// Escape sequences split across reads are kept until they are complete,
+// unless final is true, which means that the escape delay expired and the
+// bytes read must be taken as Esc or Alt keys. Incomplete UTF-8 runes are
+// always kept until they are complete. Pasted text is kept until its end,
+// unless final is true or it is longer than pasteLimit; then, the text read
+// is delivered.
+func (d *inputDecoder) next(final bool) (ev inputEvent, ok bool) {
+	for len(d.buf) > 0 || (d.pasting && final) {
+		if text, status := d.nextPaste(final); status == csiIncomplete {
+			return inputEvent{}, false
+		} else if status == csiDecoded {
+			if text == "" {
+				continue
+			}
+			return inputEvent{paste: true, text: text}, true
+		}
+
+		if !final && escapePrefix(d.buf) {
+			return inputEvent{}, false
+		}
+
+		if n, ev, status := decodeMouse(d.buf); status == csiDecoded {
+			d.buf = d.buf[n:]
+			if ev.Type == termbox.EventNone {
+				continue
+			}
+			return inputEvent{Event: ev}, true
+		}
+
+		if n, ev, status := decodeCSI(d.buf); status == csiDecoded {
+			d.buf = d.buf[n:]
+			return inputEvent{Event: ev}, true
+		}
+
+		if len(d.buf) == 1 && d.buf[0] == '\x1b' {
+			// A lone ESC cannot be the prefix of an Alt sequence.
+			d.buf = d.buf[:0]
+			return inputEvent{Event: termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEsc}}, true
+		}
+
+		ev := termbox.ParseEvent(d.buf)
+		if ev.N == 0 {
+			switch {
+			case d.buf[0] == '\x1b':
+				// ESC followed by input that cannot be an Alt
+				// combination yet
+				d.buf = d.buf[1:]
+				return inputEvent{Event: termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEsc}}, true
+			case !utf8.FullRune(d.buf):
+				// wait for the rest of the rune
+				return inputEvent{}, false
+			default:
+				// invalid UTF-8, drop the byte
+				d.buf = d.buf[1:]
+				continue
+			}
+		}
+		d.buf = d.buf[ev.N:]
+		if ev.Type != termbox.EventNone {
+			return inputEvent{Event: ev}, true
+		}
+	}
+	return inputEvent{}, false
+}
+
+// escapePrefix returns if buf is the start of an escape sequence that is not
+// complete: a lone ESC, a CSI or SS3 sequence without its final byte, an X10
+// mouse sequence without its coordinates or an Alt combination with an
+// incomplete UTF-8 rune.
+func escapePrefix(buf []byte) bool {
+	if len(buf) == 0 || buf[0] != '\x1b' {
+		return false
+	}
+	if len(buf) == 1 {
+		return true
+	}
+	switch buf[1] {
+	case '[':
+		if len(buf) >= 3 && buf[2] == 'M' {
+			return len(buf) < 6
+		}
+		for _, b := range buf[2:] {
+			if b >= 0x40 && b <= 0x7e {
+				return false
+			}
+			if b < 0x20 || b > 0x3f {
+				// not a CSI sequence
+				return false
+			}
+		}
+		return true
+	case 'O':
+		return len(buf) == 2
+	}
+	return !utf8.FullRune(buf[1:])
+}
+
+type csiStatus int
+
+const (
+	csiNone       csiStatus = iota // not a sequence decoded by gocui
+	csiIncomplete                  // more input is needed
+	csiDecoded                     // the sequence was decoded
+)
+
+// Modifier parameters of the xterm modified-key sequences.
+const (
+	csiModShift = 1 << iota
+	csiModAlt
+	csiModCtrl
+	csiModMeta
+)
+
+// csiKeys maps the final byte of "CSI 1 ; m X" sequences to its key.
+var csiKeys = map[byte]Key{
+	'A': KeyArrowUp,
+	'B': KeyArrowDown,
+	'C': KeyArrowRight,
+	'D': KeyArrowLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'R': KeyF3,
+	'S': KeyF4,
+}
+
+// csiTildeKeys maps the first parameter of "CSI n ; m ~" sequences to its
+// key.
+var csiTildeKeys = map[int]Key{
+	1:  KeyHome,
+	2:  KeyInsert,
+	3:  KeyDelete,
+	4:  KeyEnd,
+	5:  KeyPgup,
+	6:  KeyPgdn,
+	7:  KeyHome,
+	8:  KeyEnd,
+	11: KeyF1,
+	12: KeyF2,
+	13: KeyF3,
+	14: KeyF4,
+	15: KeyF5,
+	17: KeyF6,
+	18: KeyF7,
+	19: KeyF8,
+	20: KeyF9,
+	21: KeyF10,
+	23: KeyF11,
+	24: KeyF12,
+	25: KeyF13,
+	26: KeyF14,
+	28: KeyF15,
+	29: KeyF16,
+	31: KeyF17,
+	32: KeyF18,
+	33: KeyF19,
+	34: KeyF20,
+}
+
+// inputEvent is an event read from the terminal. If paste is true, it holds
+// text pasted in bracketed paste mode instead of a termbox event.
+type inputEvent struct {
+	termbox.Event
+	paste bool
+	text  string
+}
+
+// Delimiters of the text pasted in bracketed paste mode.
+const (
+	pasteStart = "\x1b[200~"
+	pasteEnd   = "\x1b[201~"
+)
+
+// pasteLimit is the maximum length of the pasted text kept by an
+// inputDecoder. Longer text is delivered in several parts.
+const pasteLimit = 64 << 10
+
+// nextPaste consumes the text pasted in bracketed paste mode, normalizing
+// its line breaks to '\n'. If final is true, the end of the paste was lost
+// and the text read is returned.
+func (d *inputDecoder) nextPaste(final bool) (text string, status csiStatus) {
+	start := 0
+	if !d.pasting {
+		if !bytes.HasPrefix(d.buf, []byte(pasteStart)) {
+			return "", csiNone
+		}
+		start = len(pasteStart)
+	}
+
+	n := len(d.buf)
+	if end := bytes.Index(d.buf[start:], []byte(pasteEnd)); end >= 0 {
+		text, n = string(d.buf[start:start+end]), start+end+len(pasteEnd)
+		d.pasting = false
+	} else if final {
+		text = string(d.buf[start:])
+		d.pasting = false
+	} else if len(d.buf)-start > pasteLimit {
+		// keep what may be the start of the end delimiter, a rune or
+		// a line break
+		n -= len(pasteEnd) - 1
+		for n > start && (!utf8.RuneStart(d.buf[n]) || d.buf[n-1] == '\r') {
+			n--
+		}
+		text = string(d.buf[start:n])
+		d.pasting = true
+	} else {
+		return "", csiIncomplete
+	}
+	d.buf = d.buf[n:]
+
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	text = strings.Replace(text, "\r", "\n", -1)
+	return text, csiDecoded
+}
+
+// decodeCSI decodes the CSI sequences that are handled by gocui: xterm
+// modified keys ("CSI 1 ; 5 C", "CSI 3 ; 2 ~"), modifyOtherKeys and
+// "CSI u" keys ("CSI 27 ; 5 ; 13 ~", "CSI 13 ; 5 u"), backtab ("CSI Z") and
+// F13 to F20. It returns the number of bytes consumed.
+func decodeCSI(buf []byte) (n int, ev termbox.Event, status csiStatus) {
+	if len(buf) < 3 || buf[0] != '\x1b' || buf[1] != '[' {
+		return 0, ev, csiNone
+	}
+
+	// parameters are digits and ';', anything else is the final byte or
+	// belongs to a sequence not decoded here (e.g. SGR mouse).
+	i := 2
+	for i < len(buf) && (buf[i] >= '0' && buf[i] <= '9' || buf[i] == ';') {
+		i++
+	}
+	if i == len(buf) {
+		if i == 2 {
+			return 0, ev, csiNone
+		}
+		return 0, ev, csiIncomplete
+	}
+
+	var params []int
+	if i > 2 {
+		for _, s := range strings.Split(string(buf[2:i]), ";") {
+			p, err := strconv.Atoi(s)
+			if err != nil {
+				return 0, ev, csiNone
+			}
+			params = append(params, p)
+		}
+	}
+
+	ev.Type = termbox.EventKey
+	final := buf[i]
+	switch {
+	case final == 'Z' && len(params) == 0:
+		ev.Key = termbox.Key(KeyBacktab)
+	case csiKeys[final] != 0 && len(params) == 2 && params[0] == 1:
+		ev.Key = termbox.Key(csiKeys[final])
+		ev.Mod = termbox.Modifier(csiModifier(params[1]))
+	case final == '~' && len(params) == 3 && params[0] == 27:
+		ev.Key, ev.Ch, ev.Mod = csiCodepoint(params[2], params[1])
+	case final == 'u' && len(params) == 2:
+		ev.Key, ev.Ch, ev.Mod = csiCodepoint(params[0], params[1])
+	case final == '~' && len(params) >= 1 && csiTildeKeys[params[0]] != 0:
+		k := csiTildeKeys[params[0]]
+		if len(params) == 1 && k > KeyF13 {
+			// unmodified keys known by termbox
+			return 0, ev, csiNone
+		}
+		ev.Key = termbox.Key(k)
+		if len(params) == 2 {
+			ev.Mod = termbox.Modifier(csiModifier(params[1]))
+		}
+	default:
+		return 0, ev, csiNone
+	}
+	return i + 1, ev, csiDecoded
+}
+
+// csiModifier converts the modifier parameter of a CSI sequence into a
+// Modifier.
+func csiModifier(p int) Modifier {
+	p--
+	var mod Modifier
+	if p&csiModShift != 0 {
+		mod |= ModShift
+	}
+	if p&(csiModAlt|csiModMeta) != 0 {
+		mod |= ModAlt
+	}
+	if p&csiModCtrl != 0 {
+		mod |= ModCtrl
+	}
+	return mod
+}
+
+// csiCodepoint returns the key, rune and modifiers corresponding to a
+// codepoint reported by the modifyOtherKeys and "CSI u" protocols. Ctrl
+// combinations with a legacy Key, like Ctrl+A, are reported with that Key.
+func csiCodepoint(code, p int) (termbox.Key, rune, termbox.Modifier) {
+	mod := csiModifier(p)
+
+	var key Key
+	switch {
+	case code == int(KeyEnter), code == int(KeyTab), code == int(KeyEsc),
+		code == int(KeyBackspace2), code == int(KeySpace):
+		key = Key(code)
+	case mod&ModCtrl != 0 && code >= 'a' && code <= 'z':
+		key = KeyCtrlA + Key(code-'a')
+		mod &^= ModCtrl
+	default:
+		return 0, rune(code), termbox.Modifier(mod)
+	}
+	if key == KeyTab && mod == ModShift {
+		key, mod = KeyBacktab, ModNone
+	}
+	return termbox.Key(key), 0, termbox.Modifier(mod)
+}
+
+// SGR mouse button bits.
+const (
+	sgrButtonMask = 3
+	sgrShift      = 4
+	sgrMeta       = 8
+	sgrCtrl       = 16
+	sgrMotion     = 32
+	sgrWheel      = 64
+)
+
+// decodeMouse decodes xterm SGR (1006) mouse sequences
+// ("CSI < b ; x ; y M" and "CSI < b ; x ; y m"), including the state of the
+// modifier keys, which is not reported by termbox. It returns the number of
+// bytes consumed.
+func decodeMouse(buf []byte) (n int, ev termbox.Event, status csiStatus) {
+	if len(buf) < 3 || buf[0] != '\x1b' || buf[1] != '[' || buf[2] != '<' {
+		return 0, ev, csiNone
+	}
+
+	i := 3
+	for i < len(buf) && (buf[i] >= '0' && buf[i] <= '9' || buf[i] == ';') {
+		i++
+	}
+	if i == len(buf) {
+		return 0, ev, csiIncomplete
+	}
+	if buf[i] != 'M' && buf[i] != 'm' {
+		return 0, ev, csiNone
+	}
+
+	params := strings.Split(string(buf[3:i]), ";")
+	if len(params) != 3 {
+		return 0, ev, csiNone
+	}
+	var p [3]int
+	for j, s := range params {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, ev, csiNone
+		}
+		p[j] = v
+	}
+	b := p[0]
+
+	switch {
+	case b&sgrWheel != 0 && b&sgrButtonMask == 0:
+		ev.Key = termbox.MouseWheelUp
+	case b&sgrWheel != 0 && b&sgrButtonMask == 1:
+		ev.Key = termbox.MouseWheelDown
+	case b&sgrWheel != 0:
+		// horizontal wheel, not supported
+		return i + 1, termbox.Event{Type: termbox.EventNone}, csiDecoded
+	case buf[i] == 'm' || b&sgrButtonMask == 3:
+		ev.Key = termbox.MouseRelease
+	case b&sgrButtonMask == 0:
+		ev.Key = termbox.MouseLeft
+	case b&sgrButtonMask == 1:
+		ev.Key = termbox.MouseMiddle
+	default:
+		ev.Key = termbox.MouseRight
+	}
+
+	var mod Modifier
+	if b&sgrShift != 0 {
+		mod |= ModShift
+	}
+	if b&sgrMeta != 0 {
+		mod |= ModAlt
+	}
+	if b&sgrCtrl != 0 {
+		mod |= ModCtrl
+	}
+	ev.Mod = termbox.Modifier(mod)
+	if b&sgrMotion != 0 {
+		ev.Mod |= termbox.ModMotion
+	}
+
+	ev.Type = termbox.EventMouse
+	ev.MouseX, ev.MouseY = p[1]-1, p[2]-1
+	return i + 1, ev, csiDecoded
+}
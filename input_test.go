@@ -0,0 +1,236 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nsf/termbox-go"
+)
+
+func TestDecodeCSI(t *testing.T) {
+	tests := []struct {
+		in     string
+		n      int
+		key    Key
+		ch     rune
+		mod    Modifier
+		status csiStatus
+	}{
+		// unmodified keys known by termbox
+		{"\x1b[2~", 0, 0, 0, 0, csiNone},
+		{"\x1b[3~", 0, 0, 0, 0, csiNone},
+		{"\x1b[5~", 0, 0, 0, 0, csiNone},
+		{"\x1b[6~", 0, 0, 0, 0, csiNone},
+		{"\x1b[15~", 0, 0, 0, 0, csiNone},
+		{"\x1b[A", 0, 0, 0, 0, csiNone},
+
+		// F13 to F20
+		{"\x1b[25~", 5, KeyF13, 0, ModNone, csiDecoded},
+		{"\x1b[26~", 5, KeyF14, 0, ModNone, csiDecoded},
+		{"\x1b[34~", 5, KeyF20, 0, ModNone, csiDecoded},
+		{"\x1b[26;2~", 7, KeyF14, 0, ModShift, csiDecoded},
+
+		// modified keys
+		{"\x1b[1;5C", 6, KeyArrowRight, 0, ModCtrl, csiDecoded},
+		{"\x1b[1;2A", 6, KeyArrowUp, 0, ModShift, csiDecoded},
+		{"\x1b[1;3H", 6, KeyHome, 0, ModAlt, csiDecoded},
+		{"\x1b[1;6D", 6, KeyArrowLeft, 0, ModCtrl | ModShift, csiDecoded},
+		{"\x1b[3;5~", 6, KeyDelete, 0, ModCtrl, csiDecoded},
+		{"\x1b[5;3~", 6, KeyPgup, 0, ModAlt, csiDecoded},
+		{"\x1b[Z", 3, KeyBacktab, 0, ModNone, csiDecoded},
+
+		// modifyOtherKeys and "CSI u"
+		{"\x1b[27;5;13~", 10, KeyEnter, 0, ModCtrl, csiDecoded},
+		{"\x1b[13;2u", 7, KeyEnter, 0, ModShift, csiDecoded},
+		{"\x1b[97;5u", 7, KeyCtrlA, 0, ModNone, csiDecoded},
+		{"\x1b[9;2u", 6, KeyBacktab, 0, ModNone, csiDecoded},
+		{"\x1b[120;3u", 8, 0, 'x', ModAlt, csiDecoded},
+
+		// trailing input is not consumed
+		{"\x1b[1;5Cx", 6, KeyArrowRight, 0, ModCtrl, csiDecoded},
+
+		// incomplete and foreign sequences
+		{"\x1b[1;5", 0, 0, 0, 0, csiIncomplete},
+		{"\x1b[", 0, 0, 0, 0, csiNone},
+		{"\x1b[<0;1;1M", 0, 0, 0, 0, csiNone},
+		{"\x1bOP", 0, 0, 0, 0, csiNone},
+		{"x", 0, 0, 0, 0, csiNone},
+	}
+
+	for _, tt := range tests {
+		n, ev, status := decodeCSI([]byte(tt.in))
+		if status != tt.status || n != tt.n {
+			t.Errorf("decodeCSI(%q): got n=%d status=%d, want n=%d status=%d", tt.in, n, status, tt.n, tt.status)
+			continue
+		}
+		if status != csiDecoded {
+			continue
+		}
+		if Key(ev.Key) != tt.key || ev.Ch != tt.ch || Modifier(ev.Mod) != tt.mod {
+			t.Errorf("decodeCSI(%q) = key %v, ch %q, mod %v; want key %v, ch %q, mod %v",
+				tt.in, ev.Key, ev.Ch, ev.Mod, tt.key, tt.ch, tt.mod)
+		}
+	}
+}
+
+// decodeAll feeds the chunks to a decoder, as consecutive reads, and returns
+// the events decoded. If final is true, the escape delay expires after the
+// last chunk.
+func decodeAll(chunks []string, final bool) []termbox.Event {
+	var (
+		dec inputDecoder
+		evs []termbox.Event
+	)
+	collect := func(final bool) {
+		for {
+			ev, ok := dec.next(final)
+			if !ok {
+				return
+			}
+			evs = append(evs, ev.Event)
+		}
+	}
+	for _, c := range chunks {
+		dec.feed([]byte(c))
+		collect(false)
+	}
+	if final {
+		collect(true)
+	}
+	return evs
+}
+
+func TestInputDecoderSplitInput(t *testing.T) {
+	// termbox is not initialized, so it is in InputEsc mode and only the
+	// sequences decoded by gocui are known.
+	type key struct {
+		key termbox.Key
+		ch  rune
+		mod termbox.Modifier
+	}
+	tests := []struct {
+		chunks []string
+		final  bool
+		want   []key
+	}{
+		// rune split across reads
+		{[]string{"a\xc3", "\xa9b"}, false, []key{{0, 'a', 0}, {0, 'é', 0}, {0, 'b', 0}}},
+		{[]string{"\xe2\x82", "\xac"}, true, []key{{0, '€', 0}}},
+		// invalid bytes are dropped alone
+		{[]string{"\xffa\xc3(b"}, false, []key{{0, 'a', 0}, {0, '(', 0}, {0, 'b', 0}}},
+		// escape sequences split across reads
+		{[]string{"\x1b", "[1;5C"}, false, []key{{termbox.Key(KeyArrowRight), 0, termbox.Modifier(ModCtrl)}}},
+		{[]string{"x\x1b[1;", "2A"}, false, []key{{0, 'x', 0}, {termbox.Key(KeyArrowUp), 0, termbox.Modifier(ModShift)}}},
+		{[]string{"\x1b[26", "~"}, false, []key{{termbox.Key(KeyF14), 0, 0}}},
+		// Esc once the escape delay expires
+		{[]string{"\x1b"}, false, nil},
+		{[]string{"\x1b"}, true, []key{{termbox.KeyEsc, 0, 0}}},
+		{[]string{"\x1b["}, false, nil},
+		{[]string{"\x1b["}, true, []key{{termbox.KeyEsc, 0, 0}, {0, '[', 0}}},
+		{[]string{"\x1bx"}, false, []key{{termbox.KeyEsc, 0, 0}, {0, 'x', 0}}},
+	}
+
+	for _, tt := range tests {
+		evs := decodeAll(tt.chunks, tt.final)
+		var got []key
+		for _, ev := range evs {
+			got = append(got, key{ev.Key, ev.Ch, ev.Mod})
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.chunks, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q: got %v, want %v", tt.chunks, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestInputDecoderPaste(t *testing.T) {
+	var dec inputDecoder
+	dec.feed([]byte("a\x1b[200~x\r\ny"))
+	if ev, ok := dec.next(false); !ok || ev.paste || ev.Ch != 'a' {
+		t.Fatalf("got %+v, %v; want 'a'", ev, ok)
+	}
+	if ev, ok := dec.next(false); ok {
+		t.Fatalf("got %+v before the end of the paste", ev)
+	}
+	dec.feed([]byte("\x1b[201~b"))
+	if ev, ok := dec.next(false); !ok || !ev.paste || ev.text != "x\ny" {
+		t.Fatalf("got %+v, %v; want paste %q", ev, ok, "x\ny")
+	}
+	if ev, ok := dec.next(false); !ok || ev.paste || ev.Ch != 'b' {
+		t.Fatalf("got %+v, %v; want 'b'", ev, ok)
+	}
+}
+
+func TestInputDecoderPasteWithoutEnd(t *testing.T) {
+	var dec inputDecoder
+	dec.feed([]byte("\x1b[200~ab\rc"))
+	if ev, ok := dec.next(false); ok {
+		t.Fatalf("got %+v before the end of the paste", ev)
+	}
+	if !dec.incomplete() {
+		t.Fatal("incomplete paste: got incomplete false, want true")
+	}
+
+	// the escape delay expires without the end of the paste
+	if ev, ok := dec.next(true); !ok || !ev.paste || ev.text != "ab\nc" {
+		t.Fatalf("got %+v, %v; want paste %q", ev, ok, "ab\nc")
+	}
+	if dec.incomplete() {
+		t.Fatal("after the paste: got incomplete true, want false")
+	}
+	dec.feed([]byte("x"))
+	if ev, ok := dec.next(false); !ok || ev.paste || ev.Ch != 'x' {
+		t.Fatalf("got %+v, %v; want 'x'", ev, ok)
+	}
+}
+
+func TestInputDecoderLongPaste(t *testing.T) {
+	text := strings.Repeat("é\r\n", pasteLimit/2)
+	input := pasteStart + text + pasteEnd + "x"
+
+	var (
+		dec   inputDecoder
+		paste string
+		evs   []inputEvent
+	)
+	for i := 0; i < len(input); i += 1000 {
+		end := i + 1000
+		if end > len(input) {
+			end = len(input)
+		}
+		dec.feed([]byte(input[i:end]))
+		for {
+			ev, ok := dec.next(false)
+			if !ok {
+				break
+			}
+			evs = append(evs, ev)
+		}
+	}
+
+	if len(evs) < 3 {
+		t.Fatalf("got %d events, want the paste in several parts and 'x'", len(evs))
+	}
+	for _, ev := range evs[:len(evs)-1] {
+		if !ev.paste {
+			t.Fatalf("got %+v, want paste", ev)
+		}
+		paste += ev.text
+	}
+	if want := strings.Replace(text, "\r\n", "\n", -1); paste != want {
+		t.Errorf("got paste of %d bytes, want %d bytes", len(paste), len(want))
+	}
+	if ev := evs[len(evs)-1]; ev.paste || ev.Ch != 'x' {
+		t.Errorf("got %+v, want 'x'", ev)
+	}
+}
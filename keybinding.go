@@ -4,15 +4,29 @@
 
 package gocui
 
-import "github.com/nsf/termbox-go"
+import (
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nsf/termbox-go"
+)
 
 // Keybidings are used to link a given key-press event with a handler.
 type keybinding struct {
 	viewName string
+	match    func(*View) bool
+	keymap   string
 	key      Key
 	ch       rune
 	mod      Modifier
 	handler  func(*Gui, *View) error
+	priority int
+
+	mouseHandler func(*Gui, *View, *MouseEvent) error
+
+	category    string
+	description string
 }
 
 // newKeybinding returns a new Keybinding object.
@@ -32,12 +46,116 @@ func (kb *keybinding) matchKeypress(key Key, ch rune, mod Modifier) bool {
 	return kb.key == key && kb.ch == ch && kb.mod == mod
 }
 
+// Keybinding describes a configured keybinding. It is returned by
+// Gui.Keybindings and can be used to build help screens.
+type Keybinding struct {
+	ViewName    string // "" means all views
+	Keymap      string // "" means all keymaps
+	Key         Key
+	Ch          rune
+	Mod         Modifier
+	Priority    int
+	Category    string
+	Description string
+}
+
+// KeyString returns a human readable representation of the key combination
+// of the keybinding, in the format accepted by ParseKey.
+func (kb Keybinding) KeyString() string {
+	return keyString(kb.Key, kb.Ch, kb.Mod)
+}
+
+// export returns the public description of the keybinding.
+func (kb *keybinding) export() Keybinding {
+	return Keybinding{
+		ViewName:    kb.viewName,
+		Keymap:      kb.keymap,
+		Key:         kb.key,
+		Ch:          kb.ch,
+		Mod:         kb.mod,
+		Priority:    kb.priority,
+		Category:    kb.category,
+		Description: kb.description,
+	}
+}
+
+// matchKeymap returns if the keybinding belongs to the given keymap.
+// Keybindings without keymap are always active.
+func (kb *keybinding) matchKeymap(keymap string) bool {
+	return kb.keymap == "" || kb.keymap == keymap
+}
+
+// precedes returns if the keybinding must be executed before other when both
+// match the same event. Keybindings with higher priority go first and, with
+// the same priority, the most specific one.
+func (kb *keybinding) precedes(other *keybinding) bool {
+	if kb.priority != other.priority {
+		return kb.priority > other.priority
+	}
+	return kb.specificity() > other.specificity()
+}
+
+// specificity returns how specific is the scope of the keybinding. Keybindings
+// for a given view are more specific than the ones matching view patterns or
+// predicates, which in turn are more specific than global keybindings.
+func (kb *keybinding) specificity() int {
+	switch {
+	case kb.isGlobal():
+		return 0
+	case kb.match != nil || isViewPattern(kb.viewName):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// isGlobal returns if the keybinding applies to all views.
+func (kb *keybinding) isGlobal() bool {
+	return kb.viewName == "" && kb.match == nil
+}
+
 // matchView returns if the keybinding matches the current view.
 func (kb *keybinding) matchView(v *View) bool {
+	if kb.match != nil {
+		return v != nil && kb.match(v)
+	}
 	if kb.viewName == "" {
 		return true
 	}
-	return v != nil && kb.viewName == v.name
+	if v == nil {
+		return false
+	}
+	if kb.viewName == v.name {
+		return true
+	}
+	if isViewPattern(kb.viewName) {
+		ok, _ := path.Match(kb.viewName, v.name)
+		return ok
+	}
+	return false
+}
+
+// isViewPattern returns if the view name of a keybinding is a glob pattern.
+func isViewPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// checkViewName returns path.ErrBadPattern if the view name of a keybinding
+// is a malformed pattern.
+func checkViewName(name string) error {
+	if !isViewPattern(name) {
+		return nil
+	}
+	_, err := path.Match(name, "")
+	return err
+}
+
+// MatchTag returns a predicate, to be used with SetKeybindingFunc, that
+// matches the views with the given tag.
+func MatchTag(tag string) func(*View) bool {
+	return func(v *View) bool {
+		return v.HasTag(tag)
+	}
 }
 
 // Key represents special keys or keys combinations.
@@ -76,6 +194,19 @@ const (
 	MouseWheelDown = Key(termbox.MouseWheelDown)
 )
 
+// Special keys decoded by gocui. Shift+Tab is reported as KeyBacktab.
+const (
+	KeyF13 Key = 0xFFFF - 64 - iota
+	KeyF14
+	KeyF15
+	KeyF16
+	KeyF17
+	KeyF18
+	KeyF19
+	KeyF20
+	KeyBacktab
+)
+
 // Keys combinations.
 const (
 	KeyCtrlTilde      Key = Key(termbox.KeyCtrlTilde)
@@ -130,8 +261,176 @@ const (
 // in combination with Keys or Runes when a new keybinding is defined.
 type Modifier termbox.Modifier
 
-// Modifiers.
+// Modifiers. ModShift and ModCtrl are reported for the keys that have no
+// dedicated Key, like Ctrl+Arrow or Shift+F5, if the terminal supports the
+// xterm modified-key sequences. Ctrl combinations with a dedicated Key, like
+// KeyCtrlA, are not reported with ModCtrl.
 const (
-	ModNone Modifier = Modifier(0)
-	ModAlt           = Modifier(termbox.ModAlt)
+	ModNone  Modifier = Modifier(0)
+	ModAlt            = Modifier(termbox.ModAlt)
+	ModShift          = Modifier(1 << 2)
+	ModCtrl           = Modifier(1 << 3)
 )
+
+// keyNames maps the names accepted by ParseKey to their corresponding Key.
+var keyNames = map[string]Key{
+	"f1":           KeyF1,
+	"f2":           KeyF2,
+	"f3":           KeyF3,
+	"f4":           KeyF4,
+	"f5":           KeyF5,
+	"f6":           KeyF6,
+	"f7":           KeyF7,
+	"f8":           KeyF8,
+	"f9":           KeyF9,
+	"f10":          KeyF10,
+	"f11":          KeyF11,
+	"f12":          KeyF12,
+	"f13":          KeyF13,
+	"f14":          KeyF14,
+	"f15":          KeyF15,
+	"f16":          KeyF16,
+	"f17":          KeyF17,
+	"f18":          KeyF18,
+	"f19":          KeyF19,
+	"f20":          KeyF20,
+	"backtab":      KeyBacktab,
+	"insert":       KeyInsert,
+	"delete":       KeyDelete,
+	"home":         KeyHome,
+	"end":          KeyEnd,
+	"pgup":         KeyPgup,
+	"pgdn":         KeyPgdn,
+	"up":           KeyArrowUp,
+	"down":         KeyArrowDown,
+	"left":         KeyArrowLeft,
+	"right":        KeyArrowRight,
+	"tab":          KeyTab,
+	"enter":        KeyEnter,
+	"esc":          KeyEsc,
+	"space":        KeySpace,
+	"backspace":    KeyBackspace,
+	"backspace2":   KeyBackspace2,
+	"mouseleft":    MouseLeft,
+	"mousemiddle":  MouseMiddle,
+	"mouseright":   MouseRight,
+	"mouserelease": MouseRelease,
+	"wheelup":      MouseWheelUp,
+	"wheeldown":    MouseWheelDown,
+	"ctrl+~":       KeyCtrlTilde,
+	"ctrl+space":   KeyCtrlSpace,
+	"ctrl+2":       KeyCtrl2,
+	"ctrl+3":       KeyCtrl3,
+	"ctrl+4":       KeyCtrl4,
+	"ctrl+5":       KeyCtrl5,
+	"ctrl+6":       KeyCtrl6,
+	"ctrl+7":       KeyCtrl7,
+	"ctrl+8":       KeyCtrl8,
+	"ctrl+[":       KeyCtrlLsqBracket,
+	"ctrl+\\":      KeyCtrlBackslash,
+	"ctrl+]":       KeyCtrlRsqBracket,
+	"ctrl+/":       KeyCtrlSlash,
+	"ctrl+_":       KeyCtrlUnderscore,
+}
+
+// keyLabels maps every Key to its preferred name. When a Key has several
+// names, the one without the "ctrl+" prefix wins.
+var keyLabels = map[Key]string{}
+
+func init() {
+	for ch := 'a'; ch <= 'z'; ch++ {
+		keyNames["ctrl+"+string(ch)] = Key(KeyCtrlA + Key(ch-'a'))
+	}
+	for name, k := range keyNames {
+		label, ok := keyLabels[k]
+		if !ok || betterLabel(name, label) {
+			keyLabels[k] = name
+		}
+	}
+}
+
+// betterLabel returns if name is preferred over label to represent a key.
+func betterLabel(name, label string) bool {
+	nctrl, lctrl := strings.HasPrefix(name, "ctrl+"), strings.HasPrefix(label, "ctrl+")
+	if nctrl != lctrl {
+		return !nctrl
+	}
+	if len(name) != len(label) {
+		return len(name) < len(label)
+	}
+	return name < label
+}
+
+// keyString returns the name of the key combination formed by key or ch and
+// mod.
+func keyString(key Key, ch rune, mod Modifier) string {
+	var s string
+	if mod&ModCtrl != 0 {
+		s += "ctrl+"
+	}
+	if mod&ModAlt != 0 {
+		s += "alt+"
+	}
+	if mod&ModShift != 0 {
+		s += "shift+"
+	}
+	switch ch {
+	case 0:
+		return s + keyLabels[key]
+	case ' ':
+		return s + keyLabels[KeySpace]
+	}
+	return s + string(ch)
+}
+
+// ParseKey parses a key description like "ctrl+s", "alt+x", "shift+f5",
+// "ctrl+left", "enter" or "q" and returns the corresponding Key or rune, in a
+// form that can be passed to SetKeybinding, and its modifiers. Named keys are
+// case insensitive, single characters are taken literally. Characters can
+// only be combined with "alt+": terminals report Shift as the shifted
+// character and Ctrl only with the keys that have a Ctrl Key, like "ctrl+a",
+// so the rest of combinations are rejected.
+func ParseKey(s string) (key interface{}, mod Modifier, err error) {
+	name := s
+	for {
+		m, rest := parseModifier(name)
+		if m == ModNone {
+			break
+		}
+		mod |= m
+		name = rest
+	}
+
+	lname := strings.ToLower(name)
+	if mod&ModCtrl != 0 {
+		// Ctrl combinations with a dedicated Key
+		if k, ok := keyNames["ctrl+"+lname]; ok {
+			return k, mod &^ ModCtrl, nil
+		}
+	}
+	if k, ok := keyNames[lname]; ok {
+		if k == KeyTab && mod&ModShift != 0 {
+			return KeyBacktab, mod &^ ModShift, nil
+		}
+		return k, mod, nil
+	}
+	if utf8.RuneCountInString(name) == 1 && mod&(ModCtrl|ModShift) == 0 {
+		ch, _ := utf8.DecodeRuneInString(name)
+		return ch, mod, nil
+	}
+	return nil, 0, ErrUnknownKey
+}
+
+// parseModifier parses a modifier prefix ("alt+", "shift+" or "ctrl+") of a
+// key description. It returns ModNone if s does not start with a modifier.
+func parseModifier(s string) (mod Modifier, rest string) {
+	for _, m := range []struct {
+		prefix string
+		mod    Modifier
+	}{{"alt+", ModAlt}, {"shift+", ModShift}, {"ctrl+", ModCtrl}} {
+		if len(s) > len(m.prefix) && strings.EqualFold(s[:len(m.prefix)], m.prefix) {
+			return m.mod, s[len(m.prefix):]
+		}
+	}
+	return ModNone, s
+}
@@ -0,0 +1,46 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"path"
+	"testing"
+)
+
+func TestMatchView(t *testing.T) {
+	tests := []struct {
+		viewName string
+		view     string
+		want     bool
+	}{
+		{"", "main", true},
+		{"main", "main", true},
+		{"main", "other", false},
+		{"list-*", "list-1", true},
+		{"list-*", "list", false},
+		{"a*b", "a*b", true},
+		{"a*b", "axb", true},
+		{"[x", "[x", true},
+		{"[x", "x", false},
+	}
+
+	for _, tt := range tests {
+		kb := newKeybinding(tt.viewName, 0, 'q', ModNone, nil)
+		if got := kb.matchView(&View{name: tt.view}); got != tt.want {
+			t.Errorf("keybinding for %q matches view %q: got %v, want %v", tt.viewName, tt.view, got, tt.want)
+		}
+	}
+}
+
+func TestSetKeybindingBadPattern(t *testing.T) {
+	g := &Gui{}
+	nop := func(*Gui, *View) error { return nil }
+	if err := g.SetKeybinding("[x", 'q', ModNone, nop); err != path.ErrBadPattern {
+		t.Errorf("got error %v, want %v", err, path.ErrBadPattern)
+	}
+	if err := g.SetKeybinding("list-[0-9]", 'q', ModNone, nop); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+}
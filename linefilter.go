@@ -0,0 +1,213 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jroimartin/gocui/fuzzy"
+)
+
+// lineQuery is a fuzzy query that filters the lines of a view.
+type lineQuery struct {
+	query     string
+	rank      bool                    // sort the lines by score
+	positions map[int][]int           // matched runes of each line shown
+	matches   map[string]*fuzzy.Match // cached matches, nil if no match
+}
+
+// SetLineFilter hides the lines of the view's internal buffer for which keep
+// returns false. The buffer is not modified: the coordinates of the view are
+// mapped to the lines shown, so Line, Word, the cursor and the selection keep
+// working with the original lines. If keep is nil, all the lines are shown.
+//
+// The cursor stays on the same line if it is still shown, otherwise it moves
+// to the first line. The filter is applied again every time the buffer is
+// modified. While lines are hidden, the lines of editable views cannot be
+// broken or joined, and nothing can be written below the last line shown.
+func (v *View) SetLineFilter(keep func(line string) bool) {
+	v.updateLines(func() {
+		v.lineFilter = keep
+	}, false)
+}
+
+// FilterLines hides the lines of the view's internal buffer that do not
+// match query, using package fuzzy, and highlights the matched runes. The
+// lines keep their order. An empty query shows all the lines. It can be
+// combined with SetLineFilter.
+func (v *View) FilterLines(query string) {
+	v.updateLines(func() {
+		v.setQuery(query, false)
+	}, false)
+}
+
+// RankLines is like FilterLines, but the lines are sorted by score, from the
+// best match, and the cursor moves to the first line. This allows to use a
+// view as a list of candidates, for instance in a command palette.
+func (v *View) RankLines(query string) {
+	v.updateLines(func() {
+		v.setQuery(query, true)
+	}, true)
+}
+
+// setQuery sets the query of the view. The matches of the current query are
+// kept if it does not change.
+func (v *View) setQuery(query string, rank bool) {
+	switch {
+	case query == "":
+		v.query = nil
+	case v.query == nil || v.query.query != query:
+		v.query = &lineQuery{query: query, rank: rank}
+	default:
+		v.query.rank = rank
+	}
+}
+
+// updateLines calls change, which modifies the lines shown by the view, and
+// keeps the cursor on the same line if it is still shown, unless top is
+// true. Otherwise, the cursor moves to the first line. The selection is
+// cleared if its ends are hidden.
+func (v *View) updateLines(change func(), top bool) {
+	v.refreshViewLines()
+	p := v.cursorPosition()
+
+	change()
+	v.tainted = true
+	v.refreshViewLines()
+
+	shown := make(map[int]bool)
+	for _, vline := range v.viewLines {
+		shown[vline.linesY] = true
+	}
+	if v.sel.active && (!shown[v.sel.anchor.y] || !shown[v.sel.head.y]) {
+		v.ClearSelection()
+	}
+	if shown[p.y] && !top {
+		v.moveCursorTo(p)
+		return
+	}
+	v.ox, v.oy, v.cx, v.cy = 0, 0, 0, 0
+}
+
+// LinesShown returns the numbers of the lines of the view's internal buffer
+// that are not hidden by its line filter or query, in the order they are
+// shown.
+func (v *View) LinesShown() []int {
+	v.refreshViewLines()
+	var ys []int
+	for i, vline := range v.viewLines {
+		if i == 0 || vline.linesY != v.viewLines[i-1].linesY {
+			ys = append(ys, vline.linesY)
+		}
+	}
+	return ys
+}
+
+// filtered returns if some lines of the view can be hidden.
+func (v *View) filtered() bool {
+	return v.lineFilter != nil || v.query != nil
+}
+
+// hiddenRow returns the point of the view's lines shown for the point p of
+// the internal buffer, which is not shown. It is placed at the start of the
+// next line shown, or of the first one if the lines are ranked. Points after
+// the last line shown are placed at its end.
+func (v *View) hiddenRow(p position) (vx, vy int) {
+	if len(v.viewLines) == 0 || (v.query != nil && v.query.rank) {
+		return 0, 0
+	}
+	for i, vline := range v.viewLines {
+		if vline.linesY > p.y {
+			return 0, i
+		}
+	}
+	last := len(v.viewLines) - 1
+	return len(v.viewLines[last].line), last
+}
+
+// lineShown returns if the line y of the internal buffer passes the line
+// filter of the view. The query is not considered.
+func (v *View) lineShown(y int) bool {
+	if v.lineFilter == nil {
+		return true
+	}
+	return v.lineFilter(v.lineString(y))
+}
+
+// lineString returns the line y of the internal buffer.
+func (v *View) lineString(y int) string {
+	return strings.Replace(lineType(v.lines[y]).String(), "\x00", " ", -1)
+}
+
+// shownLines returns the lines of the internal buffer shown, in order,
+// matching them against the query of the view.
+func (v *View) shownLines() []int {
+	var ys []int
+	for y := range v.lines {
+		if v.lineShown(y) {
+			ys = append(ys, y)
+		}
+	}
+	q := v.query
+	if q == nil {
+		return ys
+	}
+
+	if q.matches == nil || len(q.matches) > 2*len(v.lines) {
+		// drop the matches of old versions of the lines
+		q.matches = make(map[string]*fuzzy.Match)
+	}
+	var matches []fuzzy.Match
+	for i, y := range ys {
+		str := v.lineString(y)
+		m, ok := q.matches[str]
+		if !ok {
+			if mm, ok := fuzzy.MatchString(q.query, str); ok {
+				m = &mm
+			}
+			q.matches[str] = m
+		}
+		if m != nil {
+			mm := *m
+			mm.Index = i
+			matches = append(matches, mm)
+		}
+	}
+	fuzzy.Sort(matches)
+	q.positions = make(map[int][]int, len(matches))
+	shown := make([]int, len(matches))
+	for i, m := range matches {
+		shown[i] = ys[m.Index]
+		q.positions[shown[i]] = m.Positions
+	}
+	if !q.rank {
+		sort.Ints(shown)
+	}
+	return shown
+}
+
+// queryMatchAt returns if the rune (x, y) of the internal buffer was matched
+// by the query of the view.
+func (v *View) queryMatchAt(x, y int) bool {
+	positions := v.query.positions[y]
+	i := sort.SearchInts(positions, x)
+	return i < len(positions) && positions[i] == x
+}
+
+// matchColors returns the colors used to draw the runes matched by the query
+// of the view, given the colors of the cell.
+func (v *View) matchColors(fgColor, bgColor Attribute) (Attribute, Attribute) {
+	if v.MatchFgColor == ColorDefault && v.MatchBgColor == ColorDefault {
+		return fgColor | AttrBold | AttrUnderline, bgColor
+	}
+	if v.MatchFgColor != ColorDefault {
+		fgColor = v.MatchFgColor
+	}
+	if v.MatchBgColor != ColorDefault {
+		bgColor = v.MatchBgColor
+	}
+	return fgColor, bgColor
+}
@@ -0,0 +1,479 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/nsf/termbox-go"
+)
+
+// ClickMode configures the behavior of mouse clicks. Its values can be
+// combined using bitwise OR (|).
+type ClickMode int
+
+// Click modes.
+const (
+	// ClickCursor moves the cursor of the clicked view to the pointer.
+	ClickCursor ClickMode = 1 << iota
+
+	// ClickFocus makes the clicked view the current one.
+	ClickFocus
+)
+
+// MouseAction represents the phase of a mouse interaction.
+type MouseAction int
+
+// Mouse actions.
+const (
+	// MouseActionPress is reported when a button is pressed or the wheel
+	// is moved.
+	MouseActionPress MouseAction = iota
+
+	// MouseActionRelease is reported when a button is released.
+	MouseActionRelease
+
+	// MouseActionMove is reported when the pointer moves with no button
+	// pressed. Only terminals with any-motion reporting send it.
+	MouseActionMove
+
+	// MouseActionDrag is reported when the pointer moves with a button
+	// pressed.
+	MouseActionDrag
+)
+
+// MouseEvent describes a mouse event.
+type MouseEvent struct {
+	// X and Y are the coordinates of the pointer, relative to the top-left
+	// corner of the terminal.
+	X, Y int
+
+	// ViewX and ViewY are the coordinates of the pointer, relative to the
+	// content of the view. They can be out of the view while dragging.
+	ViewX, ViewY int
+
+	// Area is the area of the view under the pointer. It is AreaNone if
+	// there is no view.
+	Area ViewArea
+
+	// Button is the button involved in the event: MouseLeft, MouseMiddle,
+	// MouseRight, MouseWheelUp or MouseWheelDown. It is 0 for
+	// MouseActionMove.
+	Button Key
+
+	// Action is the phase of the interaction.
+	Action MouseAction
+
+	// Mod contains the modifier keys pressed during the event, if reported
+	// by the terminal.
+	Mod Modifier
+
+	// Clicks is the number of consecutive clicks of the button: 1 for a
+	// single click, 2 for a double click and 3 for a triple click. Drag and
+	// release events report the value of the press that started them. See
+	// Gui.MultiClickInterval.
+	Clicks int
+}
+
+// clickState keeps track of the last mouse press to detect multi-clicks.
+type clickState struct {
+	button Key
+	x, y   int
+	t      time.Time
+	count  int
+}
+
+// maxClicks is the number of clicks after which the count starts again.
+const maxClicks = 3
+
+// countClicks returns the number of consecutive clicks of a press event.
+func (g *Gui) countClicks(me *MouseEvent, t time.Time) int {
+	c := &g.lastClick
+	if g.MultiClickInterval > 0 && c.count > 0 && c.count < maxClicks &&
+		c.button == me.Button && c.x == me.X && c.y == me.Y &&
+		t.Sub(c.t) <= g.MultiClickInterval {
+		c.count++
+	} else {
+		c.count = 1
+	}
+	c.button, c.x, c.y, c.t = me.Button, me.X, me.Y, t
+	return c.count
+}
+
+// SetMouseBinding creates a new mouse binding. Its handler receives every
+// event of the given button and modifiers that happens over the views matching
+// viewname, with the same rules as SetKeybinding. Once a button is pressed
+// over a view, the drag and release events are delivered to the same view
+// even if the pointer leaves it. button must be MouseLeft, MouseMiddle,
+// MouseRight, MouseWheelUp or MouseWheelDown.
+//
+// Mouse bindings take precedence over the keybindings of mouse keys. If a
+// handler consumes a press event, the cursor of the view is not moved. The
+// keybindings of mouse keys receive presses over the view, with its cursor
+// moved to the pointer, and drags, with the modifier termbox.ModMotion, and
+// releases, which go to the view where the button was pressed and also move
+// its cursor, unless a mouse binding, a view drag or a text selection
+// consumes them.
+func (g *Gui) SetMouseBinding(viewname string, button Key, mod Modifier, handler func(*Gui, *View, *MouseEvent) error) error {
+	switch button {
+	case MouseLeft, MouseMiddle, MouseRight, MouseWheelUp, MouseWheelDown:
+	default:
+		return errors.New("invalid button")
+	}
+	if err := checkViewName(viewname); err != nil {
+		return err
+	}
+
+	kb := newKeybinding(viewname, button, 0, mod, nil)
+	kb.mouseHandler = handler
+	g.keybindings = append(g.keybindings, kb)
+	return nil
+}
+
+// onMouse manages mouse events. It builds a MouseEvent, executes the matching
+// mouse bindings and, if none of them consumes the event, applies the
+// ClickMode to the view under the pointer and executes the keybindings of the
+// mouse key.
+func (g *Gui) onMouse(ev *termbox.Event) error {
+	mx, my := ev.MouseX, ev.MouseY
+	me := &MouseEvent{
+		X:   mx,
+		Y:   my,
+		Mod: Modifier(ev.Mod &^ termbox.ModMotion),
+	}
+
+	key := Key(ev.Key)
+	motion := ev.Mod&termbox.ModMotion != 0
+	switch {
+	case motion && (key == MouseRelease || g.mouseButton == 0):
+		me.Action = MouseActionMove
+	case motion:
+		me.Action = MouseActionDrag
+		me.Button = g.mouseButton
+	case key == MouseRelease:
+		me.Action = MouseActionRelease
+		me.Button = g.mouseButton
+	default:
+		me.Action = MouseActionPress
+		me.Button = key
+	}
+
+	var v *View
+	grabbed := (me.Action == MouseActionDrag || me.Action == MouseActionRelease) && g.mouseButton != 0
+	if grabbed {
+		v = g.mouseView
+		if v != nil {
+			me.Area = v.area(mx, my)
+		}
+	} else {
+		v, me.Area, _ = g.ViewAreaByPosition(mx, my)
+	}
+	switch {
+	case me.Action == MouseActionPress && key != MouseWheelUp && key != MouseWheelDown:
+		g.mouseButton, g.mouseView = key, v
+		me.Clicks = g.countClicks(me, time.Now())
+	case me.Action == MouseActionPress:
+		me.Clicks = 1
+	case me.Action == MouseActionRelease:
+		g.mouseButton, g.mouseView = 0, nil
+		me.Clicks = g.lastClick.count
+	case me.Action == MouseActionDrag:
+		me.Clicks = g.lastClick.count
+	}
+	if v != nil {
+		me.ViewX, me.ViewY = mx-v.x0-1, my-v.y0-1
+	}
+
+	if err := g.updateHover(me); err != nil {
+		return err
+	}
+
+	if g.viewDrag != nil && (me.Action == MouseActionDrag || me.Action == MouseActionRelease) {
+		return g.updateViewDrag(me)
+	}
+	if g.startViewDrag(v, me) {
+		return nil
+	}
+	if g.selectView != nil && (me.Action == MouseActionDrag || me.Action == MouseActionRelease) {
+		g.updateMouseSelection(me)
+		return nil
+	}
+	if v != nil && v.MouseSelect && me.Action == MouseActionPress && me.Button == MouseLeft && me.Area == AreaContent {
+		g.startMouseSelection(v, me)
+	}
+
+	if me.Button != 0 {
+		matched, err := g.execMouseBindings(v, me)
+		if err != nil {
+			return err
+		}
+		if matched {
+			return nil
+		}
+	}
+
+	if v != nil && v.ScrollOnWheel && (key == MouseWheelUp || key == MouseWheelDown) {
+		n := v.WheelLines
+		if n == 0 {
+			n = 3
+		}
+		if key == MouseWheelUp {
+			n = -n
+		}
+		if me.Mod&ModShift != 0 {
+			v.scroll(n, 0)
+		} else {
+			v.scroll(0, n)
+		}
+		return nil
+	}
+
+	if grabbed {
+		// drags and releases go to the view where the button was
+		// pressed, even if the pointer left it; drags are reported to
+		// the keybindings of mouse keys as termbox does, with the
+		// termbox.ModMotion modifier
+		if v == nil {
+			return nil
+		}
+		if maxX, maxY := v.Size(); g.ClickMode&ClickCursor != 0 && maxX > 0 && maxY > 0 {
+			if err := v.SetCursor(v.contentPosition(mx, my)); err != nil {
+				return err
+			}
+		}
+		_, err := g.execKeybindings(v, ev)
+		return err
+	}
+	if motion {
+		return nil
+	}
+	v, area, err := g.ViewAreaByPosition(mx, my)
+	if err != nil {
+		return nil
+	}
+	if me.Action == MouseActionPress && key != MouseWheelUp && key != MouseWheelDown &&
+		g.ClickMode&ClickFocus != 0 {
+		if _, err := g.SetCurrentView(v.name); err != nil {
+			return err
+		}
+	}
+	if maxX, maxY := v.Size(); area == AreaContent && g.ClickMode&ClickCursor != 0 && maxX > 0 && maxY > 0 {
+		if err := v.SetCursor(v.contentPosition(mx, my)); err != nil {
+			return err
+		}
+	}
+	if _, err := g.execKeybindings(v, ev); err != nil {
+		return err
+	}
+	return nil
+}
+
+// updateHover keeps track of the view under the pointer, calling the
+// OnMouseEnter, OnMouseLeave and OnMouseMove hooks of the views.
+func (g *Gui) updateHover(me *MouseEvent) error {
+	v, area, _ := g.ViewAreaByPosition(me.X, me.Y)
+	if v != g.hoverView {
+		if old := g.hoverView; old != nil && old.OnMouseLeave != nil {
+			if err := old.OnMouseLeave(g, old); err != nil {
+				return err
+			}
+		}
+		g.hoverView = v
+		if v != nil && v.OnMouseEnter != nil {
+			if err := v.OnMouseEnter(g, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	if v != nil && me.Action == MouseActionMove && v.OnMouseMove != nil {
+		hme := *me
+		hme.ViewX, hme.ViewY = me.X-v.x0-1, me.Y-v.y0-1
+		hme.Area = area
+		return v.OnMouseMove(g, v, &hme)
+	}
+	return nil
+}
+
+// execMouseBindings executes the mouse binding handlers that match the passed
+// view and event, in order of precedence, until one of them consumes the
+// event. The value of matched is true if the event was consumed and no errors.
+func (g *Gui) execMouseBindings(v *View, me *MouseEvent) (matched bool, err error) {
+	var kbs []*keybinding
+	for _, kb := range g.keybindings {
+		if kb.mouseHandler == nil {
+			continue
+		}
+		if kb.matchKeypress(me.Button, 0, me.Mod) && kb.matchView(v) && kb.matchKeymap(g.keymap) {
+			kbs = append(kbs, kb)
+		}
+	}
+	sort.SliceStable(kbs, func(i, j int) bool {
+		return kbs[i].precedes(kbs[j])
+	})
+
+	for _, kb := range kbs {
+		if err := kb.mouseHandler(g, v, me); err != nil {
+			if err == ErrNotHandled {
+				continue
+			}
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// Edges of a view affected by a drag operation.
+const (
+	edgeLeft = 1 << iota
+	edgeRight
+	edgeTop
+	edgeBottom
+	edgeAll = edgeLeft | edgeRight | edgeTop | edgeBottom
+)
+
+// viewDrag represents a view being moved or resized with the mouse.
+type viewDrag struct {
+	v              *View
+	edges          int // edges moved by the drag, edgeAll to move the view
+	x, y           int // position where the drag started
+	x0, y0, x1, y1 int // geometry of the view when the drag started
+}
+
+// startViewDrag starts moving or resizing v if the event is a press of the
+// left button over its frame and the view allows it. It returns if a drag
+// operation has started.
+func (g *Gui) startViewDrag(v *View, me *MouseEvent) bool {
+	if v == nil || !v.Frame || me.Action != MouseActionPress || me.Button != MouseLeft {
+		return false
+	}
+	if me.Area != AreaBorder && me.Area != AreaTitle {
+		return false
+	}
+
+	edges := 0
+	if me.X == v.x0 {
+		edges |= edgeLeft
+	} else if me.X == v.x1 {
+		edges |= edgeRight
+	}
+	if me.Y == v.y0 {
+		edges |= edgeTop
+	} else if me.Y == v.y1 {
+		edges |= edgeBottom
+	}
+
+	switch {
+	case edges == edgeTop && v.Draggable:
+		edges = edgeAll
+	case !v.Resizable:
+		return false
+	}
+
+	g.viewDrag = &viewDrag{
+		v:     v,
+		edges: edges,
+		x:     me.X,
+		y:     me.Y,
+		x0:    v.x0,
+		y0:    v.y0,
+		x1:    v.x1,
+		y1:    v.y1,
+	}
+	return true
+}
+
+// updateViewDrag moves or resizes the dragged view according to the event.
+func (g *Gui) updateViewDrag(me *MouseEvent) error {
+	d := g.viewDrag
+	if me.Action == MouseActionRelease {
+		g.viewDrag = nil
+	}
+
+	dx, dy := me.X-d.x, me.Y-d.y
+	x0, y0, x1, y1 := d.x0, d.y0, d.x1, d.y1
+	if d.edges == edgeAll {
+		// keep the view inside the terminal or, if it does not fit, its
+		// top-left corner
+		if x1+dx > g.maxX-1 {
+			dx = g.maxX - 1 - x1
+		}
+		if x0+dx < 0 {
+			dx = -x0
+		}
+		if y1+dy > g.maxY-1 {
+			dy = g.maxY - 1 - y1
+		}
+		if y0+dy < 0 {
+			dy = -y0
+		}
+		x0, y0, x1, y1 = x0+dx, y0+dy, x1+dx, y1+dy
+	} else {
+		v := d.v
+		if d.edges&edgeLeft != 0 {
+			x0 = x1 - 1 - clampSize(x1-(x0+dx)-1, v.MinWidth, v.MaxWidth)
+		}
+		if d.edges&edgeRight != 0 {
+			x1 = x0 + 1 + clampSize(x1+dx-x0-1, v.MinWidth, v.MaxWidth)
+		}
+		if d.edges&edgeTop != 0 {
+			y0 = y1 - 1 - clampSize(y1-(y0+dy)-1, v.MinHeight, v.MaxHeight)
+		}
+		if d.edges&edgeBottom != 0 {
+			y1 = y0 + 1 + clampSize(y1+dy-y0-1, v.MinHeight, v.MaxHeight)
+		}
+
+		// the moved edges cannot leave the terminal
+		if d.edges&edgeLeft != 0 && x0 < 0 {
+			x0 = 0
+		}
+		if d.edges&edgeRight != 0 && x1 > g.maxX-1 {
+			x1 = g.maxX - 1
+		}
+		if d.edges&edgeTop != 0 && y0 < 0 {
+			y0 = 0
+		}
+		if d.edges&edgeBottom != 0 && y1 > g.maxY-1 {
+			y1 = g.maxY - 1
+		}
+		if x1-x0 < minDragSize+1 || y1-y0 < minDragSize+1 {
+			// no room for the minimum size
+			return nil
+		}
+	}
+
+	v := d.v
+	if x0 == v.x0 && y0 == v.y0 && x1 == v.x1 && y1 == v.y1 {
+		return nil
+	}
+	v.x0, v.y0, v.x1, v.y1 = x0, y0, x1, y1
+	v.tainted = true
+	if v.OnGeometryChange != nil {
+		return v.OnGeometryChange(g, v, x0, y0, x1, y1)
+	}
+	return nil
+}
+
+// minDragSize is the minimum width and height of the views resized with the
+// mouse.
+const minDragSize = 1
+
+// clampSize returns size constrained to [min, max]. A zero max means no
+// limit. The result is never less than minDragSize.
+func clampSize(size, min, max int) int {
+	if max > 0 && size > max {
+		size = max
+	}
+	if size < min {
+		size = min
+	}
+	if size < minDragSize {
+		size = minDragSize
+	}
+	return size
+}
@@ -0,0 +1,313 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+// SearchOptions configure the searches in views.
+type SearchOptions struct {
+	// If Regexp is true, the pattern is a regular expression with the
+	// syntax of package regexp. Otherwise, it is plain text.
+	Regexp bool
+
+	// If IgnoreCase is true, the case of the letters is ignored.
+	IgnoreCase bool
+
+	// If Backward is true, the search goes backward: Search selects the
+	// closest match before the cursor and SearchNext moves to the previous
+	// match.
+	Backward bool
+}
+
+// searchMatch is a match of a search, located in the line y of the
+// internal buffer, between the runes x0, included, and x1, excluded.
+type searchMatch struct {
+	y, x0, x1 int
+}
+
+// search is the state of a search in a view.
+type search struct {
+	re       *regexp.Regexp
+	backward bool
+	matches  []searchMatch
+	lines    map[int][]int // indexes of the matches of each line
+	current  int           // index of the current match, -1 if none
+}
+
+// Search searches pattern in the view's internal buffer, highlighting all the
+// matches, and moves the cursor to the closest match after the cursor, or
+// before it if opts.Backward is true. The origin of the view is displaced if
+// necessary. An empty pattern clears the search. It returns an error if the
+// pattern is not a valid regular expression.
+func (v *View) Search(pattern string, opts SearchOptions) error {
+	if pattern == "" {
+		v.ClearSearch()
+		return nil
+	}
+	if !opts.Regexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+
+	v.search = &search{re: re, backward: opts.Backward, current: -1}
+	v.findMatches()
+	if len(v.search.matches) == 0 {
+		return nil
+	}
+
+	p := v.cursorPosition()
+	s := v.search
+	if opts.Backward {
+		s.current = len(s.matches) - 1
+		for i := len(s.matches) - 1; i >= 0; i-- {
+			if m := s.matches[i]; !p.before(position{m.x0, m.y}) {
+				s.current = i
+				break
+			}
+		}
+	} else {
+		s.current = 0
+		for i, m := range s.matches {
+			if !(position{m.x0, m.y}).before(p) {
+				s.current = i
+				break
+			}
+		}
+	}
+	v.moveToMatch()
+	return nil
+}
+
+// SearchNext moves the cursor to the next match of the search, in its
+// direction, wrapping around the buffer. It returns false if there are no
+// matches.
+func (v *View) SearchNext() bool {
+	if v.search == nil || v.search.backward {
+		return v.searchStep(-1)
+	}
+	return v.searchStep(1)
+}
+
+// SearchPrev moves the cursor to the previous match of the search, in its
+// direction, wrapping around the buffer. It returns false if there are no
+// matches.
+func (v *View) SearchPrev() bool {
+	if v.search == nil || v.search.backward {
+		return v.searchStep(1)
+	}
+	return v.searchStep(-1)
+}
+
+// SearchStatus returns the number of the current match, starting at 1, and
+// the number of matches of the search. current is 0 if there is no current
+// match.
+func (v *View) SearchStatus() (current, total int) {
+	if v.search == nil {
+		return 0, 0
+	}
+	v.refreshSearch()
+	return v.search.current + 1, len(v.search.matches)
+}
+
+// ClearSearch clears the search of the view and its highlighting.
+func (v *View) ClearSearch() {
+	v.search = nil
+}
+
+// searchStep moves the cursor dir matches forward.
+func (v *View) searchStep(dir int) bool {
+	if v.search == nil {
+		return false
+	}
+	v.refreshSearch()
+	s := v.search
+	n := len(s.matches)
+	if n == 0 {
+		return false
+	}
+	s.current = ((s.current+dir)%n + n) % n
+	v.moveToMatch()
+	return true
+}
+
+// moveToMatch moves the cursor to the start of the current match.
+func (v *View) moveToMatch() {
+	m := v.search.matches[v.search.current]
+	v.moveCursorTo(position{m.x0, m.y})
+}
+
+// refreshSearch finds the matches of the search again if the buffer was
+// modified.
+func (v *View) refreshSearch() {
+	if v.tainted {
+		v.findMatches()
+	}
+}
+
+// findMatches finds the matches of the search in the view's internal buffer.
+func (v *View) findMatches() {
+	s := v.search
+	s.matches, s.lines = nil, make(map[int][]int)
+	for y, line := range v.lines {
+		str := lineType(line).String()
+		for _, loc := range s.re.FindAllStringIndex(str, -1) {
+			if loc[0] == loc[1] {
+				continue
+			}
+			x0 := utf8.RuneCountInString(str[:loc[0]])
+			x1 := x0 + utf8.RuneCountInString(str[loc[0]:loc[1]])
+			s.lines[y] = append(s.lines[y], len(s.matches))
+			s.matches = append(s.matches, searchMatch{y, x0, x1})
+		}
+	}
+	if s.current >= len(s.matches) {
+		s.current = len(s.matches) - 1
+	}
+}
+
+// searchMatchAt returns if the rune (x, y) of the internal buffer is part of
+// a match of the search and, in that case, if it is the current match.
+func (v *View) searchMatchAt(x, y int) (match, current bool) {
+	for _, i := range v.search.lines[y] {
+		if m := v.search.matches[i]; x >= m.x0 && x < m.x1 {
+			return true, i == v.search.current
+		}
+	}
+	return false, false
+}
+
+// searchColors returns the colors used to draw the matches of the search.
+func (v *View) searchColors(current bool) (fgColor, bgColor Attribute) {
+	fgColor, bgColor = v.SearchFgColor, v.SearchBgColor
+	if fgColor == ColorDefault && bgColor == ColorDefault {
+		fgColor, bgColor = ColorBlack, ColorYellow
+	}
+	if current {
+		fgColor |= AttrReverse
+	}
+	return fgColor, bgColor
+}
+
+// SearchPrompt is a Manager that shows a single-line view over the bottom of
+// a view to search incrementally in it. The matches are highlighted while
+// typing, Ctrl-N and Ctrl-P move to the next and previous ones, Enter closes
+// the prompt keeping the search and Esc closes it clearing the search.
+type SearchPrompt struct {
+	name   string
+	target *View
+
+	// Title is the title of the prompt, which is followed by the number of
+	// matches.
+	Title string
+
+	// Options configure the searches.
+	Options SearchOptions
+}
+
+// NewSearchPrompt returns a new SearchPrompt that uses a view with the given
+// name.
+func NewSearchPrompt(name string) *SearchPrompt {
+	return &SearchPrompt{name: name, Title: "Search"}
+}
+
+// Open opens the prompt to search in v. It can be used directly as a
+// keybinding handler, for instance bound to '/' or Ctrl-F.
+func (s *SearchPrompt) Open(g *Gui, v *View) error {
+	if v == nil || v.name == s.name {
+		return nil
+	}
+	s.target = v
+	if err := s.Layout(g); err != nil {
+		return err
+	}
+	_, err := g.SetCurrentView(s.name)
+	return err
+}
+
+// Layout draws the prompt if it is open.
+func (s *SearchPrompt) Layout(g *Gui) error {
+	if s.target == nil {
+		return nil
+	}
+	t := s.target
+	if tv, err := g.View(t.name); err != nil || tv != t {
+		// the target was deleted
+		return s.close(g, false)
+	}
+
+	y0 := t.y1 - 2
+	if y0 < t.y0 {
+		y0 = t.y0
+	}
+	v, err := g.SetView(s.name, t.x0, y0, t.x1, y0+2)
+	if err != nil {
+		if err != ErrUnknownView {
+			return err
+		}
+		v.Editable = true
+		v.SingleLine = true
+		v.Editor = EditorFunc(s.edit)
+		v.OnSubmit = func(g *Gui, v *View, text string) error {
+			return s.close(g, true)
+		}
+		v.OnCancel = func(g *Gui, v *View) error {
+			return s.close(g, false)
+		}
+	}
+
+	v.Title = s.Title
+	if v.inputText() != "" {
+		current, total := t.SearchStatus()
+		v.Title = fmt.Sprintf("%s (%d/%d)", s.Title, current, total)
+	}
+	_, err = g.SetViewOnTop(s.name)
+	return err
+}
+
+// edit is the editor of the prompt, which searches the text in the target
+// view after every change.
+func (s *SearchPrompt) edit(v *View, key Key, ch rune, mod Modifier) {
+	switch key {
+	case KeyCtrlN:
+		s.target.SearchNext()
+		return
+	case KeyCtrlP:
+		s.target.SearchPrev()
+		return
+	}
+
+	text := v.inputText()
+	simpleEditor(v, key, ch, mod)
+	if newText := v.inputText(); newText != text {
+		v.SetInputError(s.target.Search(newText, s.Options))
+	}
+}
+
+// close closes the prompt and focuses its target view. The search is
+// cleared unless keep is true.
+func (s *SearchPrompt) close(g *Gui, keep bool) error {
+	t := s.target
+	s.target = nil
+	if err := g.DeleteView(s.name); err != nil && err != ErrUnknownView {
+		return err
+	}
+	if !keep {
+		t.ClearSearch()
+	}
+	if _, err := g.SetCurrentView(t.name); err != nil && err != ErrUnknownView {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"errors"
+	"strings"
+)
+
+// position is a point of the view's internal buffer.
+type position struct {
+	x, y int
+}
+
+// before returns if p is located before q in the buffer.
+func (p position) before(q position) bool {
+	return p.y < q.y || (p.y == q.y && p.x < q.x)
+}
+
+// selection is a range of the view's internal buffer, delimited by an anchor,
+// where the selection started, and a head, which is moved to extend it. The
+// cell under the head is not part of the selection.
+type selection struct {
+	active       bool
+	anchor, head position
+}
+
+// bounds returns the start and end of the selection, in buffer order.
+func (s selection) bounds() (start, end position) {
+	if s.head.before(s.anchor) {
+		return s.head, s.anchor
+	}
+	return s.anchor, s.head
+}
+
+// contains returns if the cell (x, y) of the buffer is selected.
+func (s selection) contains(x, y int) bool {
+	if !s.active {
+		return false
+	}
+	start, end := s.bounds()
+	p := position{x, y}
+	return !p.before(start) && p.before(end)
+}
+
+// SetSelection selects the text of the view's internal buffer between the
+// points (x0, y0), included, and (x1, y1), excluded. Points are clamped to the
+// buffer contents.
+func (v *View) SetSelection(x0, y0, x1, y1 int) error {
+	if x0 < 0 || y0 < 0 || x1 < 0 || y1 < 0 {
+		return errors.New("invalid point")
+	}
+	v.sel = selection{
+		active: true,
+		anchor: v.clampPosition(position{x0, y0}),
+		head:   v.clampPosition(position{x1, y1}),
+	}
+	return nil
+}
+
+// SelectionRange returns the start, included, and end, excluded, of the
+// selection, as points of the view's internal buffer. ok is false if there is
+// no selection.
+func (v *View) SelectionRange() (x0, y0, x1, y1 int, ok bool) {
+	if !v.sel.active {
+		return 0, 0, 0, 0, false
+	}
+	start, end := v.sel.bounds()
+	start, end = v.clampPosition(start), v.clampPosition(end)
+	return start.x, start.y, end.x, end.y, true
+}
+
+// ClearSelection removes the selection of the view.
+func (v *View) ClearSelection() {
+	v.sel = selection{}
+}
+
+// Selection returns the selected text. Lines are separated by '\n'.
+func (v *View) Selection() string {
+	x0, y0, x1, y1, ok := v.SelectionRange()
+	if !ok {
+		return ""
+	}
+	return v.textRange(position{x0, y0}, position{x1, y1})
+}
+
+// textRange returns the text of the internal buffer between the points
+// start, included, and end, excluded, which must be clamped to the buffer
+// contents. Lines are separated by '\n'.
+func (v *View) textRange(start, end position) string {
+	var lines []string
+	for y := start.y; y <= end.y && y < len(v.lines); y++ {
+		line := v.lines[y]
+		x0, x1 := 0, len(line)
+		if y == start.y {
+			x0 = start.x
+		}
+		if y == end.y {
+			x1 = end.x
+		}
+		lines = append(lines, lineType(line[x0:x1]).String())
+	}
+	return strings.Replace(strings.Join(lines, "\n"), "\x00", " ", -1)
+}
+
+// ExtendSelection moves the cursor like MoveCursor, extending the selection
+// to the new position. If there is no selection, it starts at the cursor.
+func (v *View) ExtendSelection(dx, dy int) {
+	v.startKeyboardSelection()
+	v.MoveCursor(dx, dy, false)
+	v.sel.head = v.cursorPosition()
+}
+
+// ExtendSelectionWord moves the cursor to the next word, or the previous one
+// if back is true, extending the selection to the new position.
+func (v *View) ExtendSelectionWord(back bool) {
+	v.startKeyboardSelection()
+	v.MoveCursorWord(back)
+	v.sel.head = v.cursorPosition()
+}
+
+// SelectWord selects the word under the cursor.
+func (v *View) SelectWord() {
+	v.selectWord(v.cursorPosition())
+	v.moveCursorTo(v.sel.head)
+}
+
+// SelectLine selects the line under the cursor, including its line break.
+func (v *View) SelectLine() {
+	v.selectLine(v.cursorPosition())
+	v.moveCursorTo(v.sel.head)
+}
+
+// SelectAll selects all the text of the view.
+func (v *View) SelectAll() {
+	if len(v.lines) == 0 {
+		return
+	}
+	v.sel = selection{
+		active: true,
+		head:   v.clampPosition(position{0, len(v.lines)}),
+	}
+	v.moveCursorTo(v.sel.head)
+}
+
+// startKeyboardSelection prepares the selection to be extended from the
+// keyboard. A new selection starts at the cursor, which is moved to the head
+// of an existing one.
+func (v *View) startKeyboardSelection() {
+	if !v.sel.active {
+		p := v.cursorPosition()
+		v.sel = selection{active: true, anchor: p, head: p}
+		return
+	}
+	v.moveCursorTo(v.sel.head)
+}
+
+// cursorPosition returns the point of the buffer under the cursor, clamped to
+// the buffer contents.
+func (v *View) cursorPosition() position {
+	v.refreshViewLines()
+	x, y, err := v.realPosition(v.cx, v.cy)
+	if err != nil {
+		return position{}
+	}
+	return v.clampPosition(position{x, y})
+}
+
+// clampPosition returns the closest point of the buffer contents to p.
+func (v *View) clampPosition(p position) position {
+	if len(v.lines) == 0 {
+		return position{}
+	}
+	if p.y >= len(v.lines) {
+		p.y = len(v.lines) - 1
+		p.x = len(v.lines[p.y])
+	}
+	if p.x > len(v.lines[p.y]) {
+		p.x = len(v.lines[p.y])
+	}
+	return p
+}
+
+// bufferPosition returns the point of the buffer shown at the point (x, y) of
+// the view, clamped to the buffer contents. Points above the view are placed
+// at the start of its first row.
+func (v *View) bufferPosition(x, y int) position {
+	v.refreshViewLines()
+	if y < 0 {
+		x, y = 0, 0
+	}
+	if x < 0 {
+		x = 0
+	}
+	vy := v.oy + y
+	if vy < 0 || len(v.viewLines) == 0 {
+		return position{}
+	}
+	if vy >= len(v.viewLines) {
+		return v.clampPosition(position{0, len(v.lines)})
+	}
+	vline := v.viewLines[vy]
+	vx := v.ox + x
+	if vx > len(vline.line) {
+		vx = len(vline.line)
+	}
+	return v.clampPosition(position{vline.linesX + vx, vline.linesY})
+}
+
+// selectWord selects the word of the buffer at p.
+func (v *View) selectWord(p position) {
+	p = v.clampPosition(p)
+	if p.y >= len(v.lines) {
+		return
+	}
+	line := v.lines[p.y]
+	start, end := p.x, p.x
+	for start > 0 && !indexFunc(line[start-1].chr) {
+		start--
+	}
+	for end < len(line) && !indexFunc(line[end].chr) {
+		end++
+	}
+	v.sel = selection{active: true, anchor: position{start, p.y}, head: position{end, p.y}}
+}
+
+// selectLine selects the line of the buffer at p, including its line break.
+func (v *View) selectLine(p position) {
+	if len(v.lines) == 0 {
+		return
+	}
+	p = v.clampPosition(p)
+	v.sel = selection{
+		active: true,
+		anchor: position{0, p.y},
+		head:   v.clampPosition(position{0, p.y + 1}),
+	}
+	if v.sel.head.y == p.y {
+		// last line
+		v.sel.head.x = len(v.lines[p.y])
+	}
+}
+
+// selectionColors returns the colors used to draw the selected text.
+func (v *View) selectionColors() (fgColor, bgColor Attribute) {
+	if v.SelectionFgColor == ColorDefault && v.SelectionBgColor == ColorDefault {
+		return v.FgColor | AttrReverse, v.BgColor
+	}
+	return v.SelectionFgColor, v.SelectionBgColor
+}
+
+// startMouseSelection starts a mouse selection in v, selecting a word on
+// double click and a line on triple click.
+func (g *Gui) startMouseSelection(v *View, me *MouseEvent) {
+	p := v.bufferPosition(me.ViewX, me.ViewY)
+	switch me.Clicks {
+	case 2:
+		v.selectWord(p)
+	case 3:
+		v.selectLine(p)
+	default:
+		v.sel = selection{active: true, anchor: p, head: p}
+	}
+	g.selectView, g.selectInit = v, v.sel
+}
+
+// updateMouseSelection extends the mouse selection of the view to the
+// position of the pointer, scrolling the view if the pointer is out of it.
+func (g *Gui) updateMouseSelection(me *MouseEvent) {
+	v := g.selectView
+	if me.Action == MouseActionRelease {
+		g.selectView = nil
+	}
+
+	_, maxY := v.Size()
+	if me.ViewY < 0 {
+		v.scroll(0, -1)
+	} else if me.ViewY >= maxY {
+		v.scroll(0, 1)
+	}
+
+	p := v.bufferPosition(me.ViewX, me.ViewY)
+	if me.Clicks < 2 {
+		v.sel.head = p
+		return
+	}
+
+	// extend by words or lines, keeping the initial one selected
+	start, end := g.selectInit.bounds()
+	if me.Clicks == 2 {
+		v.selectWord(p)
+	} else {
+		v.selectLine(p)
+	}
+	s, e := v.sel.bounds()
+	if s.before(start) {
+		v.sel.anchor, v.sel.head = end, s
+	} else {
+		v.sel.anchor, v.sel.head = start, e
+	}
+}
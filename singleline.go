@@ -0,0 +1,75 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import "strings"
+
+// editSingleLine handles the keystrokes of the editable single-line view v.
+// Enter, Esc and the vertical arrows are handled here, the rest of keys are
+// passed to the editor of the view.
+func (g *Gui) editSingleLine(v *View, key Key, ch rune, mod Modifier) error {
+	v.Wrap = false
+
+	switch {
+	case key == KeyEnter:
+		if v.Validate() != nil {
+			break
+		}
+		text := v.inputText()
+		if v.History != nil {
+			if err := v.History.Add(text); err != nil {
+				v.SetInputError(err)
+			}
+		}
+		if v.OnSubmit != nil {
+			return v.OnSubmit(g, v, text)
+		}
+	case key == KeyEsc:
+		if v.History != nil {
+			v.History.Reset()
+		}
+		if v.OnCancel != nil {
+			return v.OnCancel(g, v)
+		}
+	case key == KeyArrowUp || key == KeyArrowDown:
+		if v.History == nil || mod != ModNone {
+			break
+		}
+		var text string
+		var ok bool
+		if key == KeyArrowUp {
+			text, ok = v.History.Prev(v.inputText())
+		} else {
+			text, ok = v.History.Next()
+		}
+		if ok {
+			v.setInputText(text)
+		}
+	default:
+		v.Editor.Edit(v, key, ch, mod)
+		v.moveCursorTo(v.cursorPosition())
+	}
+	return nil
+}
+
+// inputText returns the text of the single-line view v.
+func (v *View) inputText() string {
+	return strings.Join(v.BufferLines(), " ")
+}
+
+// setInputText replaces the text of the single-line view v, moving the
+// cursor to its end.
+func (v *View) setInputText(text string) {
+	v.beginEdit(editOther)
+	defer v.endEdit()
+
+	v.SelectAll()
+	if !v.deleteSelection() {
+		v.ClearSelection()
+		return
+	}
+	v.EditWriteString(text)
+	v.moveCursorTo(v.lineEnd(v.cursorPosition()))
+}
@@ -0,0 +1,188 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+// undoLimit is the maximum number of undo steps kept by a view.
+const undoLimit = 1000
+
+// editKind classifies the edit operations, so consecutive operations of the
+// same kind can be undone at once.
+type editKind int
+
+const (
+	editOther editKind = iota // never grouped
+	editWrite
+	editDeleteBack
+	editDeleteForward
+)
+
+// cursorState is the position of the cursor and the origin of a view.
+type cursorState struct {
+	cx, cy, ox, oy int
+}
+
+// change is a modification of the view's internal buffer: the lines before,
+// starting at line y, were replaced by the lines after.
+type change struct {
+	y             int
+	before, after [][]cell
+}
+
+// undoStep is a group of changes that are undone at once.
+type undoStep struct {
+	kind          editKind
+	changes       []change
+	before, after cursorState
+}
+
+// undoHistory keeps the steps that can be undone and redone.
+type undoHistory struct {
+	undo, redo []*undoStep
+	depth      int  // nesting level of the running edit operations
+	closed     bool // the last step cannot be extended
+}
+
+// Undo reverts the last edit operation of the view. Consecutive typing or
+// deletions are reverted at once. It returns false if there was nothing to
+// undo.
+func (v *View) Undo() bool {
+	h := &v.history
+	if len(h.undo) == 0 || h.depth > 0 {
+		return false
+	}
+	step := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	step.after = v.cursorState()
+
+	for i := len(step.changes) - 1; i >= 0; i-- {
+		c := step.changes[i]
+		v.replaceLines(c.y, len(c.after), c.before)
+	}
+	v.setCursorState(step.before)
+
+	h.redo = append(h.redo, step)
+	h.closed = true
+	return true
+}
+
+// Redo applies again the last edit operation reverted by Undo. It returns
+// false if there was nothing to redo.
+func (v *View) Redo() bool {
+	h := &v.history
+	if len(h.redo) == 0 || h.depth > 0 {
+		return false
+	}
+	step := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+
+	for _, c := range step.changes {
+		v.replaceLines(c.y, len(c.before), c.after)
+	}
+	v.setCursorState(step.after)
+
+	h.undo = append(h.undo, step)
+	h.closed = true
+	return true
+}
+
+// ClearHistory discards the undo and redo history of the view.
+func (v *View) ClearHistory() {
+	v.history = undoHistory{}
+}
+
+// beginEdit starts an edit operation of the given kind. The changes made
+// until the matching call to endEdit are undone at once, together with the
+// ones of the previous operation if both have the same kind and the cursor
+// was not moved in between. Edit operations can be nested.
+func (v *View) beginEdit(kind editKind) {
+	h := &v.history
+	h.depth++
+	if h.depth > 1 {
+		return
+	}
+
+	cur := v.cursorState()
+	if n := len(h.undo); n > 0 && !h.closed && kind != editOther {
+		last := h.undo[n-1]
+		if last.kind == kind && last.after == cur {
+			return
+		}
+	}
+	h.undo = append(h.undo, &undoStep{kind: kind, before: cur})
+	if len(h.undo) > undoLimit {
+		h.undo = h.undo[1:]
+	}
+	h.closed = false
+}
+
+// endEdit ends an edit operation started by beginEdit.
+func (v *View) endEdit() {
+	h := &v.history
+	h.depth--
+	if h.depth > 0 {
+		return
+	}
+	last := h.undo[len(h.undo)-1]
+	if len(last.changes) == 0 {
+		// nothing changed, do not keep an empty step
+		h.undo = h.undo[:len(h.undo)-1]
+		return
+	}
+	last.after = v.cursorState()
+}
+
+// recordChange records the change of the lines of the internal buffer
+// starting at line y in the current undo step.
+func (v *View) recordChange(y int, before, after [][]cell) {
+	h := &v.history
+	if h.depth == 0 {
+		v.beginEdit(editOther)
+		defer v.endEdit()
+	}
+	last := h.undo[len(h.undo)-1]
+	last.changes = append(last.changes, change{y: y, before: before, after: after})
+	h.redo = nil
+}
+
+// copyLines returns a copy of the lines [y0, y1) of the internal buffer.
+func (v *View) copyLines(y0, y1 int) [][]cell {
+	if y1 > len(v.lines) {
+		y1 = len(v.lines)
+	}
+	if y0 >= y1 {
+		return nil
+	}
+	lines := make([][]cell, y1-y0)
+	for i := range lines {
+		lines[i] = append([]cell(nil), v.lines[y0+i]...)
+	}
+	return lines
+}
+
+// replaceLines replaces the n lines of the internal buffer starting at line
+// y with a copy of lines.
+func (v *View) replaceLines(y, n int, lines [][]cell) {
+	v.tainted = true
+	v.sel = selection{}
+
+	tail := v.lines[y+n:]
+	newLines := make([][]cell, 0, y+len(lines)+len(tail))
+	newLines = append(newLines, v.lines[:y]...)
+	for _, l := range lines {
+		newLines = append(newLines, append([]cell(nil), l...))
+	}
+	v.lines = append(newLines, tail...)
+}
+
+// cursorState returns the position of the cursor and the origin of the view.
+func (v *View) cursorState() cursorState {
+	return cursorState{v.cx, v.cy, v.ox, v.oy}
+}
+
+// setCursorState sets the position of the cursor and the origin of the
+// view.
+func (v *View) setCursorState(s cursorState) {
+	v.cx, v.cy, v.ox, v.oy = s.cx, s.cy, s.ox, s.oy
+}
@@ -29,6 +29,20 @@ type View struct {
 
 	ei *escapeInterpreter // used to decode ESC sequences on Write
 
+	sel     selection   // selected text
+	history undoHistory // edit operations that can be undone
+
+	menu *completionMenu // completion menu, nil if it is closed
+	hint string          // ghost text shown after the cursor
+
+	inputErr  error // error of the last filtered edit or validation
+	filterErr bool  // inputErr was returned by a filter
+
+	search *search // current search, nil if there is none
+
+	lineFilter func(line string) bool // lines shown, nil if all
+	query      *lineQuery             // fuzzy query, nil if there is none
+
 	// BgColor and FgColor allow to configure the background and foreground
 	// colors of the View.
 	BgColor, FgColor Attribute
@@ -46,6 +60,59 @@ type View struct {
 	// default.
 	Editor Editor
 
+	// Clipboard is used by Copy, Cut and Paste. By default, it is the
+	// clipboard of the GUI.
+	Clipboard Clipboard
+
+	// If SingleLine is true, the editable view is an input field: Enter
+	// calls OnSubmit, Esc calls OnCancel, the arrow keys Up and Down browse
+	// History, and the view scrolls horizontally to keep the cursor
+	// visible. Line breaks are never inserted. Wrap is ignored: it is set to
+	// false when the view is drawn or edited.
+	SingleLine bool
+
+	// OnSubmit is called with the text of the single-line view when Enter
+	// is pressed.
+	OnSubmit func(g *Gui, v *View, text string) error
+
+	// OnCancel is called when Esc is pressed in the single-line view.
+	OnCancel func(g *Gui, v *View) error
+
+	// History keeps the text submitted in the single-line view. If it is
+	// not nil, submitted text is added to it; errors writing its file are
+	// reported with SetInputError.
+	History *History
+
+	// Completer provides the candidates to complete the text of the
+	// editable view. Tab opens a menu with them, which is navigated with
+	// Tab, Shift-Tab and the arrow keys, Enter accepts the selected
+	// candidate and Esc closes it. A single candidate is accepted directly.
+	Completer Completer
+
+	// If CompletionHint is true, the rest of the first candidate of the
+	// Completer is shown as ghost text after the cursor while typing at the
+	// end of a line. The right arrow accepts it. It is not shown if Mask is
+	// set.
+	CompletionHint bool
+
+	// HintFgColor is the color of the ghost text. If it is ColorDefault,
+	// bright black is used.
+	HintFgColor Attribute
+
+	// Filters are applied in order before each EditWrite and EditDelete,
+	// and can transform the written runes or reject the operations.
+	Filters []Filter
+
+	// Validator validates the text of the view when Validate is called and
+	// before OnSubmit is called in single-line views, which is not called
+	// if it fails.
+	Validator func(text string) error
+
+	// ErrorFgColor is the color of the input error, which is drawn on the
+	// bottom edge of the frame of the view. If it is ColorDefault, red is
+	// used.
+	ErrorFgColor Attribute
+
 	// Overwrite enables or disables the overwrite mode of the view.
 	Overwrite bool
 
@@ -56,6 +123,11 @@ type View struct {
 	// If Frame is true, a border will be drawn around the view.
 	Frame bool
 
+	// FrameFgColor and FrameBgColor allow to configure the colors of the
+	// frame of the view. If they are ColorDefault, the colors of the GUI are
+	// used. They are ignored when the frame is highlighted.
+	FrameFgColor, FrameBgColor Attribute
+
 	// If Wrap is true, the content that is written to this View is
 	// automatically wrapped when it is longer than its width. If true the
 	// view's x-origin will be ignored.
@@ -65,12 +137,85 @@ type View struct {
 	// text overflows. If true the view's y-origin will be ignored.
 	Autoscroll bool
 
+	// If MouseSelect is true, the text of the view can be selected by
+	// dragging the mouse with the left button. Double and triple clicks
+	// select words and lines. See Selection.
+	MouseSelect bool
+
+	// SelectionFgColor and SelectionBgColor are used to draw the selected
+	// text. If both are ColorDefault, the selection is drawn in reverse
+	// video.
+	SelectionFgColor, SelectionBgColor Attribute
+
+	// If Draggable is true and the view has a frame, it can be moved by
+	// dragging the top edge of the frame with the left button.
+	Draggable bool
+
+	// If Resizable is true and the view has a frame, it can be resized by
+	// dragging the edges or corners of the frame with the left button. If
+	// Draggable is also true, the top edge moves the view instead.
+	Resizable bool
+
+	// MinWidth, MinHeight, MaxWidth and MaxHeight constrain the size of
+	// the view, as returned by Size, when it is resized with the mouse. A
+	// zero maximum means no limit. Views moved or resized with the mouse
+	// are kept inside the terminal and are at least 1x1.
+	MinWidth, MinHeight, MaxWidth, MaxHeight int
+
+	// OnGeometryChange is called every time the view is moved or resized
+	// with the mouse. Given that managers usually set the position of
+	// their views on every layout, it can be used to persist the new
+	// geometry.
+	OnGeometryChange func(g *Gui, v *View, x0, y0, x1, y1 int) error
+
+	// If ScrollOnWheel is true, the mouse wheel scrolls the view when the
+	// pointer is over it, even if it is not the current view, without
+	// moving the cursor. With Shift, it scrolls horizontally. The
+	// keybindings of the wheel are not executed for the view.
+	ScrollOnWheel bool
+
+	// WheelLines is the number of lines scrolled by each step of the wheel
+	// when ScrollOnWheel is true. If it is 0, 3 lines are scrolled.
+	WheelLines int
+
+	// Placeholder is drawn when the internal buffer of the view is empty.
+	// It is not part of the buffer.
+	Placeholder string
+
+	// PlaceholderFgColor and PlaceholderBgColor allow to configure the
+	// colors of the placeholder. If PlaceholderFgColor is ColorDefault,
+	// bright black is used.
+	PlaceholderFgColor, PlaceholderBgColor Attribute
+
+	// SearchFgColor and SearchBgColor are used to draw the matches of the
+	// search (see Search). If both are ColorDefault, black on yellow is
+	// used. The current match is drawn in reverse video.
+	SearchFgColor, SearchBgColor Attribute
+
+	// MatchFgColor and MatchBgColor are used to draw the runes matched by
+	// the query of FilterLines and RankLines. If both are ColorDefault, the
+	// runes are drawn in bold and underlined.
+	MatchFgColor, MatchBgColor Attribute
+
 	// If Frame is true, Title allows to configure a title for the view.
 	Title string
 
 	// If Mask is true, the View will display the mask instead of the real
 	// content
 	Mask rune
+
+	// OnMouseEnter and OnMouseLeave are called when the mouse pointer
+	// enters or leaves the view. Gui.MouseMotion must be enabled to get them
+	// while no button is pressed.
+	OnMouseEnter, OnMouseLeave func(*Gui, *View) error
+
+	// OnMouseMove is called when the mouse pointer moves over the view with
+	// no button pressed. Gui.MouseMotion must be enabled.
+	OnMouseMove func(*Gui, *View, *MouseEvent) error
+
+	// Tags allows to classify views, so keybindings can be applied to all
+	// the views with a given tag (see MatchTag).
+	Tags []string
 }
 
 type viewLine struct {
@@ -120,37 +265,87 @@ func (v *View) Name() string {
 	return v.name
 }
 
+// ViewArea identifies a region of a view.
+type ViewArea int
+
+// View areas.
+const (
+	AreaNone    ViewArea = iota // outside of any view
+	AreaContent                 // the content of the view
+	AreaBorder                  // the frame of the view, excluding the title
+	AreaTitle                   // the title, on the top edge of the frame
+)
+
+// area returns the area of the view that contains the point (x, y), relative
+// to the top-left corner of the terminal. The edges of views without frame
+// belong to their content.
+func (v *View) area(x, y int) ViewArea {
+	if x < v.x0 || x > v.x1 || y < v.y0 || y > v.y1 {
+		return AreaNone
+	}
+	if x > v.x0 && x < v.x1 && y > v.y0 && y < v.y1 {
+		return AreaContent
+	}
+	if !v.Frame {
+		return AreaContent
+	}
+	if y == v.y0 && v.Title != "" {
+		tx0 := v.x0 + 2
+		tx1 := tx0 + len([]rune(v.Title)) - 1
+		if tx1 > v.x1-2 {
+			tx1 = v.x1 - 2
+		}
+		if x >= tx0 && x <= tx1 {
+			return AreaTitle
+		}
+	}
+	return AreaBorder
+}
+
+// contentPosition returns the point of the content of the view closest to
+// (x, y), relative to the top-left corner of the terminal. The result is
+// relative to the view.
+func (v *View) contentPosition(x, y int) (cx, cy int) {
+	maxX, maxY := v.Size()
+	cx, cy = x-v.x0-1, y-v.y0-1
+	if cx >= maxX {
+		cx = maxX - 1
+	}
+	if cx < 0 {
+		cx = 0
+	}
+	if cy >= maxY {
+		cy = maxY - 1
+	}
+	if cy < 0 {
+		cy = 0
+	}
+	return cx, cy
+}
+
+// HasTag returns if the view has the given tag.
+func (v *View) HasTag(tag string) bool {
+	for _, t := range v.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // setRune sets a rune at the given point relative to the view. It applies the
-// specified colors, taking into account if the cell must be highlighted. Also,
-// it checks if the position is valid.
+// specified colors, unless the view is masked. Also, it checks if the position
+// is valid.
 func (v *View) setRune(x, y int, ch rune, fgColor, bgColor Attribute) error {
 	maxX, maxY := v.Size()
 	if x < 0 || x >= maxX || y < 0 || y >= maxY {
 		return errors.New("invalid point")
 	}
 
-	var (
-		ry, rcy int
-		err     error
-	)
-	if v.Highlight {
-		_, ry, err = v.realPosition(x, y)
-		if err != nil {
-			return err
-		}
-		_, rcy, err = v.realPosition(v.cx, v.cy)
-		if err != nil {
-			return err
-		}
-	}
-
 	if v.Mask != 0 {
 		fgColor = v.FgColor
 		bgColor = v.BgColor
 		ch = v.Mask
-	} else if v.Highlight && ry == rcy {
-		fgColor = v.SelFgColor
-		bgColor = v.SelBgColor
 	}
 
 	termbox.SetCell(v.x0+x+1, v.y0+y+1, ch,
@@ -198,9 +393,10 @@ func (v *View) Origin() (x, y int) {
 // Write appends a byte slice into the view's internal buffer. Because
 // View implements the io.Writer interface, it can be passed as parameter
 // of functions like fmt.Fprintf, fmt.Fprintln, io.Copy, etc. Clear must
-// be called to clear the view's buffer.
+// be called to clear the view's buffer. It discards the undo history.
 func (v *View) Write(p []byte) (n int, err error) {
 	v.tainted = true
+	v.history = undoHistory{}
 
 	for _, ch := range bytes.Runes(p) {
 		switch ch {
@@ -288,6 +484,11 @@ func (v *View) Rewind() {
 func (v *View) draw() error {
 	maxX, maxY := v.Size()
 
+	if v.SingleLine {
+		// single-line views scroll horizontally
+		v.Wrap = false
+	}
+
 	if v.Wrap {
 		if maxX == 0 {
 			return errors.New("X size of the view cannot be 0")
@@ -295,35 +496,28 @@ func (v *View) draw() error {
 		v.ox = 0
 	}
 	if v.tainted {
-		v.viewLines = nil
-		for i, line := range v.lines {
-			if v.Wrap {
-				if len(line) < maxX {
-					vline := viewLine{linesX: 0, linesY: i, line: line}
-					v.viewLines = append(v.viewLines, vline)
-					continue
-				} else {
-					for n := 0; n <= len(line); n += maxX {
-						if len(line[n:]) <= maxX {
-							vline := viewLine{linesX: n, linesY: i, line: line[n:]}
-							v.viewLines = append(v.viewLines, vline)
-						} else {
-							vline := viewLine{linesX: n, linesY: i, line: line[n : n+maxX]}
-							v.viewLines = append(v.viewLines, vline)
-						}
-					}
-				}
-			} else {
-				vline := viewLine{linesX: 0, linesY: i, line: line}
-				v.viewLines = append(v.viewLines, vline)
-			}
+		if v.search != nil {
+			v.findMatches()
 		}
-		v.tainted = false
+		v.updateViewLines(maxX)
 	}
 
 	if v.Autoscroll && len(v.viewLines) > maxY {
 		v.oy = len(v.viewLines) - maxY
 	}
+
+	// line of the internal buffer under the cursor, for highlighting
+	rcy := -1
+	if v.Highlight {
+		_, y, err := v.realPosition(v.cx, v.cy)
+		switch {
+		case err == nil:
+			rcy = y
+		case !v.filtered():
+			return err
+		}
+	}
+
 	y := 0
 	for i, vline := range v.viewLines {
 		if i < v.oy {
@@ -349,6 +543,21 @@ func (v *View) draw() error {
 			if bgColor == ColorDefault {
 				bgColor = v.BgColor
 			}
+			if vline.linesY == rcy {
+				fgColor = v.SelFgColor
+				bgColor = v.SelBgColor
+			}
+			if v.query != nil && v.queryMatchAt(vline.linesX+j, vline.linesY) {
+				fgColor, bgColor = v.matchColors(fgColor, bgColor)
+			}
+			if v.search != nil {
+				if match, current := v.searchMatchAt(vline.linesX+j, vline.linesY); match {
+					fgColor, bgColor = v.searchColors(current)
+				}
+			}
+			if v.sel.contains(vline.linesX+j, vline.linesY) {
+				fgColor, bgColor = v.selectionColors()
+			}
 
 			if err := v.setRune(x, y, c.chr, fgColor, bgColor); err != nil {
 				return err
@@ -357,9 +566,128 @@ func (v *View) draw() error {
 		}
 		y++
 	}
+
+	if v.Placeholder != "" && v.isEmpty() {
+		v.drawPlaceholder()
+	}
+	if v.hint != "" {
+		return v.drawHint()
+	}
 	return nil
 }
 
+// isEmpty returns if the internal buffer of the view has no runes.
+func (v *View) isEmpty() bool {
+	for _, line := range v.lines {
+		if len(line) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// drawPlaceholder draws the placeholder of the view. It is never masked.
+func (v *View) drawPlaceholder() {
+	maxX, maxY := v.Size()
+	fgColor, bgColor := v.PlaceholderFgColor, v.PlaceholderBgColor
+	if fgColor == ColorDefault {
+		fgColor = ColorBlack | AttrBold
+	}
+	if bgColor == ColorDefault {
+		bgColor = v.BgColor
+	}
+
+	for y, line := range strings.Split(v.Placeholder, "\n") {
+		if y >= maxY {
+			break
+		}
+		x := 0
+		for _, ch := range line {
+			if x >= maxX {
+				break
+			}
+			// not masked, unlike setRune
+			termbox.SetCell(v.x0+x+1, v.y0+y+1, ch,
+				termbox.Attribute(fgColor), termbox.Attribute(bgColor))
+			x++
+		}
+	}
+}
+
+// updateViewLines updates the internal representation of the view's buffer,
+// wrapping the lines if needed.
+func (v *View) updateViewLines(maxX int) {
+	v.viewLines = nil
+	for _, i := range v.shownLines() {
+		line := v.lines[i]
+		if v.Wrap {
+			if len(line) < maxX {
+				vline := viewLine{linesX: 0, linesY: i, line: line}
+				v.viewLines = append(v.viewLines, vline)
+				continue
+			} else {
+				for n := 0; n <= len(line); n += maxX {
+					if len(line[n:]) <= maxX {
+						vline := viewLine{linesX: n, linesY: i, line: line[n:]}
+						v.viewLines = append(v.viewLines, vline)
+					} else {
+						vline := viewLine{linesX: n, linesY: i, line: line[n : n+maxX]}
+						v.viewLines = append(v.viewLines, vline)
+					}
+				}
+			}
+		} else {
+			vline := viewLine{linesX: 0, linesY: i, line: line}
+			v.viewLines = append(v.viewLines, vline)
+		}
+	}
+	v.tainted = false
+}
+
+// refreshViewLines updates the internal representation of the view's buffer
+// if it is outdated, so it can be used before the view is drawn.
+func (v *View) refreshViewLines() {
+	maxX, _ := v.Size()
+	if v.tainted && (!v.Wrap || maxX > 0) {
+		v.updateViewLines(maxX)
+	}
+}
+
+// scroll moves the origin of the view dx columns and dy lines, without
+// going beyond its content.
+func (v *View) scroll(dx, dy int) {
+	maxX, maxY := v.Size()
+	v.refreshViewLines()
+
+	maxOy := len(v.viewLines) - maxY
+	oy := v.oy + dy
+	if oy > maxOy {
+		oy = maxOy
+	}
+	if oy < 0 {
+		oy = 0
+	}
+	v.oy = oy
+
+	if v.Wrap {
+		return
+	}
+	width := 0
+	for _, vline := range v.viewLines {
+		if len(vline.line) > width {
+			width = len(vline.line)
+		}
+	}
+	ox := v.ox + dx
+	if ox > width-maxX {
+		ox = width - maxX
+	}
+	if ox < 0 {
+		ox = 0
+	}
+	v.ox = ox
+}
+
 // realPosition returns the position in the internal buffer corresponding to the
 // point (x, y) of the view.
 func (v *View) realPosition(vx, vy int) (x, y int, err error) {
@@ -370,6 +698,12 @@ func (v *View) realPosition(vx, vy int) (x, y int, err error) {
 		return 0, 0, errors.New("invalid point")
 	}
 
+	if v.filtered() && vy >= len(v.viewLines) {
+		// the rows after the lines shown do not map to the buffer,
+		// the lines that follow may be hidden
+		return 0, 0, errors.New("invalid point")
+	}
+
 	if len(v.viewLines) == 0 {
 		return vx, vy, nil
 	}
@@ -387,13 +721,17 @@ func (v *View) realPosition(vx, vy int) (x, y int, err error) {
 	return x, y, nil
 }
 
-// Clear empties the view's internal buffer.
+// Clear empties the view's internal buffer and discards the undo history.
 func (v *View) Clear() {
 	v.tainted = true
 
 	v.lines = nil
 	v.viewLines = nil
 	v.readOffset = 0
+	v.sel = selection{}
+	v.history = undoHistory{}
+	v.menu, v.hint = nil, ""
+	v.inputErr, v.filterErr = nil, false
 	v.clearRunes()
 }
 
@@ -0,0 +1,853 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VimMode is the mode of a VimEditor.
+type VimMode int
+
+// Modes of a VimEditor.
+const (
+	VimNormal VimMode = iota
+	VimInsert
+	VimVisual
+	VimCommandLine
+)
+
+// String returns the name of the mode, as shown by vim.
+func (m VimMode) String() string {
+	switch m {
+	case VimInsert:
+		return "INSERT"
+	case VimVisual:
+		return "VISUAL"
+	case VimCommandLine:
+		return "COMMAND"
+	default:
+		return "NORMAL"
+	}
+}
+
+// vimKey is a keystroke, recorded to repeat changes with ".".
+type vimKey struct {
+	key Key
+	ch  rune
+	mod Modifier
+}
+
+// vimRegister is the content of a register.
+type vimRegister struct {
+	text     string
+	linewise bool
+}
+
+// vimCommand is a parsed normal mode command: an optional register and
+// count, followed by an operator and a motion, a motion or an action.
+type vimCommand struct {
+	register    rune
+	count       int // 0 if there is no count
+	op          rune
+	motionCount int
+	motion      string
+	action      string
+}
+
+type vimStatus int
+
+const (
+	vimInvalid vimStatus = iota
+	vimIncomplete
+	vimComplete
+)
+
+const (
+	vimOperators = "dcy"
+	vimMotions   = "hjklwbe0$Gn"
+	vimActions   = "xXDCiaIAoOpPu.v/?:"
+	vimChanges   = "xXDCiaIAoOpP"
+)
+
+// VimEditor is a modal Editor with vim-style keybindings. It supports the
+// normal, insert, visual and command-line modes, the motions h, j, k, l, w,
+// b, e, 0, $, gg, G, n and N, the operators d, c and y, counts, registers
+// ("a to "z, and "+ for the clipboard of the view), undo with u and Ctrl-R,
+// repetition of the last change with ".", and searches with "/" and "?".
+// Motions that cannot move, like j on the last line, cancel the operator.
+// Text pasted in the terminal is inserted literally in any mode.
+//
+// Commands entered with ":" are passed to OnCommand. Mode and CommandLine
+// allow to show the state of the editor, for instance in a status view.
+type VimEditor struct {
+	// OnCommand is called with the text of the commands entered with ":",
+	// without the colon.
+	OnCommand func(v *View, cmd string)
+
+	mode       VimMode
+	pending    []rune   // keys of the command being typed
+	keys       []vimKey // keystrokes of the command being typed
+	cmdline    []rune   // text of the command line, including ':', '/' or '?'
+	registers  map[rune]vimRegister
+	recording  []vimKey // keystrokes of the change being recorded
+	lastChange []vimKey // keystrokes of the last change, repeated by "."
+	lastSearch string
+	searchBack bool
+	visual     position // start of the visual selection
+}
+
+// NewVimEditor returns a new VimEditor in normal mode.
+func NewVimEditor() *VimEditor {
+	return &VimEditor{registers: make(map[rune]vimRegister)}
+}
+
+// Mode returns the current mode of the editor.
+func (e *VimEditor) Mode() VimMode {
+	return e.mode
+}
+
+// CommandLine returns the text of the command line, including the leading
+// ':', '/' or '?', while the editor is in command-line mode.
+func (e *VimEditor) CommandLine() string {
+	return string(e.cmdline)
+}
+
+// Edit handles the keystroke for v.
+func (e *VimEditor) Edit(v *View, key Key, ch rune, mod Modifier) {
+	switch e.mode {
+	case VimInsert:
+		e.editInsert(v, key, ch, mod)
+	case VimCommandLine:
+		e.editCommandLine(v, key, ch, mod)
+	default:
+		e.editNormal(v, key, ch, mod)
+	}
+}
+
+// editInsert handles the keystrokes in insert mode.
+func (e *VimEditor) editInsert(v *View, key Key, ch rune, mod Modifier) {
+	if e.recording != nil {
+		e.recording = append(e.recording, vimKey{key, ch, mod})
+	}
+	if key != KeyEsc {
+		simpleEditor(v, key, ch, mod)
+		return
+	}
+
+	e.mode = VimNormal
+	if e.recording != nil {
+		e.lastChange, e.recording = e.recording, nil
+	}
+	if p := v.cursorPosition(); p.x > 0 {
+		v.moveCursorTo(position{p.x - 1, p.y})
+	}
+}
+
+// editCommandLine handles the keystrokes in command-line mode.
+func (e *VimEditor) editCommandLine(v *View, key Key, ch rune, mod Modifier) {
+	switch {
+	case key == KeyEsc:
+		e.mode, e.cmdline = VimNormal, nil
+	case key == KeyEnter:
+		line := string(e.cmdline)
+		e.mode, e.cmdline = VimNormal, nil
+		e.runCommandLine(v, line)
+	case key == KeyBackspace || key == KeyBackspace2:
+		e.cmdline = e.cmdline[:len(e.cmdline)-1]
+		if len(e.cmdline) == 0 {
+			e.mode = VimNormal
+		}
+	case key == KeySpace:
+		e.cmdline = append(e.cmdline, ' ')
+	case ch != 0 && mod == 0:
+		e.cmdline = append(e.cmdline, ch)
+	}
+}
+
+// runCommandLine executes the command entered in command-line mode.
+func (e *VimEditor) runCommandLine(v *View, line string) {
+	switch line[0] {
+	case '/', '?':
+		if line[1:] != "" {
+			e.lastSearch = line[1:]
+		}
+		e.searchBack = line[0] == '?'
+		if p, ok := e.target(v, "n", 1); ok {
+			v.moveCursorTo(p)
+		}
+	case ':':
+		if e.OnCommand != nil {
+			e.OnCommand(v, line[1:])
+		}
+	}
+}
+
+// editNormal handles the keystrokes in normal and visual modes.
+func (e *VimEditor) editNormal(v *View, key Key, ch rune, mod Modifier) {
+	switch key {
+	case KeyEsc:
+		e.reset()
+		if e.mode == VimVisual {
+			e.mode = VimNormal
+			v.ClearSelection()
+		}
+		return
+	case KeyCtrlR:
+		e.reset()
+		v.Redo()
+		return
+	case KeyArrowLeft, KeyBackspace, KeyBackspace2:
+		ch = 'h'
+	case KeyArrowDown, KeyEnter:
+		ch = 'j'
+	case KeyArrowUp:
+		ch = 'k'
+	case KeyArrowRight, KeySpace:
+		ch = 'l'
+	}
+	if ch == 0 || mod != ModNone {
+		e.reset()
+		return
+	}
+
+	e.pending = append(e.pending, ch)
+	e.keys = append(e.keys, vimKey{key, ch, mod})
+
+	if e.mode == VimVisual {
+		if reg, op, ok := parseVisualOperator(e.pending); ok {
+			e.reset()
+			e.visualOperate(v, reg, op)
+			return
+		}
+	}
+
+	cmd, status := parseVimCommand(e.pending)
+	switch status {
+	case vimIncomplete:
+		return
+	case vimInvalid:
+		e.reset()
+		return
+	}
+
+	keys := e.keys
+	e.reset()
+	e.execute(v, cmd)
+
+	if cmd.op == 'd' || cmd.op == 'c' || cmd.action != "" && strings.Contains(vimChanges, cmd.action) {
+		if e.mode == VimInsert {
+			e.recording = keys
+		} else if e.mode == VimNormal {
+			e.lastChange = keys
+		}
+	}
+	switch e.mode {
+	case VimNormal:
+		e.clampCursor(v)
+	case VimVisual:
+		e.updateVisual(v)
+	}
+}
+
+// reset discards the command being typed.
+func (e *VimEditor) reset() {
+	e.pending, e.keys = nil, nil
+}
+
+// parseVimCommand parses the keys of a normal mode command.
+func parseVimCommand(keys []rune) (cmd vimCommand, status vimStatus) {
+	i := 0
+	if len(keys) > 0 && keys[0] == '"' {
+		if len(keys) < 2 {
+			return cmd, vimIncomplete
+		}
+		cmd.register, i = keys[1], 2
+	}
+	cmd.count, i = parseVimCount(keys, i)
+	if i == len(keys) {
+		return cmd, vimIncomplete
+	}
+
+	c := keys[i]
+	switch {
+	case strings.ContainsRune(vimOperators, c):
+		cmd.op = c
+		cmd.motionCount, i = parseVimCount(keys, i+1)
+		if i == len(keys) {
+			return cmd, vimIncomplete
+		}
+		if keys[i] == c {
+			cmd.motion = string(c)
+			return cmd, vimComplete
+		}
+		cmd.motion, status = parseVimMotion(keys[i:])
+		return cmd, status
+	case strings.ContainsRune(vimActions, c) && i == len(keys)-1:
+		cmd.action = string(c)
+		return cmd, vimComplete
+	default:
+		cmd.motion, status = parseVimMotion(keys[i:])
+		return cmd, status
+	}
+}
+
+// parseVimCount parses the count that starts at keys[i], returning it and
+// the index of the next key.
+func parseVimCount(keys []rune, i int) (count, next int) {
+	for i < len(keys) && keys[i] >= '0' && keys[i] <= '9' {
+		if keys[i] == '0' && count == 0 {
+			// motion "0"
+			break
+		}
+		count = count*10 + int(keys[i]-'0')
+		i++
+	}
+	return count, i
+}
+
+// parseVimMotion parses the keys of a motion.
+func parseVimMotion(keys []rune) (motion string, status vimStatus) {
+	switch {
+	case keys[0] == 'g' && len(keys) == 1:
+		return "", vimIncomplete
+	case keys[0] == 'g' && len(keys) == 2 && keys[1] == 'g':
+		return "gg", vimComplete
+	case len(keys) == 1 && (strings.ContainsRune(vimMotions, keys[0]) || keys[0] == 'N'):
+		return string(keys[0]), vimComplete
+	}
+	return "", vimInvalid
+}
+
+// parseVisualOperator parses the keys of an operator applied to the visual
+// selection, which can be preceded by a register.
+func parseVisualOperator(keys []rune) (reg, op rune, ok bool) {
+	i := 0
+	if len(keys) == 3 && keys[0] == '"' {
+		reg, i = keys[1], 2
+	}
+	if i != len(keys)-1 || !strings.ContainsRune("dxyc", keys[i]) {
+		return 0, 0, false
+	}
+	return reg, keys[i], true
+}
+
+// execute executes a complete normal mode command.
+func (e *VimEditor) execute(v *View, cmd vimCommand) {
+	switch {
+	case cmd.op != 0:
+		e.operate(v, cmd)
+	case cmd.action != "":
+		e.act(v, cmd)
+	default:
+		if p, ok := e.target(v, cmd.motion, cmd.count); ok {
+			v.moveCursorTo(p)
+		}
+	}
+}
+
+// target returns the position where the motion moves the cursor.
+func (e *VimEditor) target(v *View, motion string, count int) (p position, ok bool) {
+	if len(v.lines) == 0 {
+		return position{}, false
+	}
+	n := count
+	if n == 0 {
+		n = 1
+	}
+	p = v.cursorPosition()
+	line := v.lines[p.y]
+
+	switch motion {
+	case "h":
+		p.x -= n
+		if p.x < 0 {
+			p.x = 0
+		}
+	case "l":
+		p.x += n
+		if p.x > len(line) {
+			p.x = len(line)
+		}
+	case "j", "k":
+		y := p.y
+		if motion == "j" {
+			p.y += n
+		} else {
+			p.y -= n
+		}
+		if p = v.clampLine(p); p.y == y {
+			// the motion fails at the edges of the buffer
+			return p, false
+		}
+	case "w", "b":
+		for i := 0; i < n; i++ {
+			p = v.wordBoundary(p, motion == "b")
+		}
+	case "e":
+		for i := 0; i < n; i++ {
+			p = v.wordEnd(p)
+		}
+	case "0":
+		p.x = 0
+	case "$":
+		p.x = len(line) - 1
+		if p.x < 0 {
+			p.x = 0
+		}
+	case "gg", "G":
+		p.x = 0
+		switch {
+		case count > 0:
+			p.y = count - 1
+		case motion == "gg":
+			p.y = 0
+		default:
+			p.y = len(v.lines) - 1
+		}
+		p = v.clampLine(p)
+	case "n", "N":
+		if e.lastSearch == "" {
+			return p, false
+		}
+		back := e.searchBack != (motion == "N")
+		for i := 0; i < n; i++ {
+			if p, ok = v.findText(e.lastSearch, p, back); !ok {
+				return p, false
+			}
+		}
+	default:
+		return p, false
+	}
+	return p, true
+}
+
+// isLinewise returns if the operators act on whole lines with the motion.
+func isLinewise(motion string) bool {
+	switch motion {
+	case "j", "k", "gg", "G", "d", "c", "y":
+		return true
+	}
+	return false
+}
+
+// operate applies the operator of cmd to the text between the cursor and
+// the target of the motion.
+func (e *VimEditor) operate(v *View, cmd vimCommand) {
+	if len(v.lines) == 0 {
+		return
+	}
+	count := cmd.count
+	if cmd.motionCount != 0 {
+		if count == 0 {
+			count = 1
+		}
+		count *= cmd.motionCount
+	}
+
+	cur := v.cursorPosition()
+	motion := cmd.motion
+	if cmd.op == 'c' && motion == "w" {
+		// "cw" behaves like "ce"
+		motion = "e"
+	}
+
+	var target position
+	if motion == string(cmd.op) {
+		if count == 0 {
+			count = 1
+		}
+		target = v.clampLine(position{0, cur.y + count - 1})
+	} else {
+		var ok bool
+		if target, ok = e.target(v, motion, count); !ok {
+			return
+		}
+	}
+
+	start, end := cur, target
+	if end.before(start) {
+		start, end = end, start
+	}
+	if isLinewise(motion) {
+		e.operateLines(v, cmd.register, cmd.op, start.y, end.y)
+		return
+	}
+	if motion == "e" || motion == "$" {
+		// inclusive motions
+		end = v.clampPosition(position{end.x + 1, end.y})
+	}
+
+	text := v.textRange(start, end)
+	e.setRegister(v, cmd.register, text, false)
+	switch cmd.op {
+	case 'd':
+		v.editDeleteText(start, end)
+	case 'c':
+		if v.editDeleteText(start, end) {
+			e.mode = VimInsert
+		}
+	case 'y':
+		v.moveCursorTo(start)
+	}
+}
+
+// operateLines applies the operator op to the lines y0 to y1.
+func (e *VimEditor) operateLines(v *View, reg, op rune, y0, y1 int) {
+	start, end := position{0, y0}, v.lineEnd(position{0, y1})
+	e.setRegister(v, reg, v.textRange(start, end)+"\n", true)
+
+	switch op {
+	case 'd':
+		if y1+1 < len(v.lines) {
+			end = position{0, y1 + 1}
+		} else if y0 > 0 {
+			start = v.lineEnd(position{0, y0 - 1})
+		}
+		if v.editDeleteText(start, end) {
+			v.moveCursorTo(v.clampLine(position{0, y0}))
+		}
+	case 'c':
+		if v.editDeleteText(start, end) {
+			e.mode = VimInsert
+		}
+	case 'y':
+		v.moveCursorTo(start)
+	}
+}
+
+// visualOperate applies the operator op to the visual selection.
+func (e *VimEditor) visualOperate(v *View, reg, op rune) {
+	x0, y0, x1, y1, ok := v.SelectionRange()
+	e.mode = VimNormal
+	v.ClearSelection()
+	if !ok {
+		return
+	}
+
+	start, end := position{x0, y0}, position{x1, y1}
+	e.setRegister(v, reg, v.textRange(start, end), false)
+	switch op {
+	case 'd', 'x':
+		v.editDeleteText(start, end)
+		e.clampCursor(v)
+	case 'c':
+		if v.editDeleteText(start, end) {
+			e.mode = VimInsert
+		}
+	case 'y':
+		v.moveCursorTo(start)
+	}
+}
+
+// act executes the action of cmd.
+func (e *VimEditor) act(v *View, cmd vimCommand) {
+	n := cmd.count
+	if n == 0 {
+		n = 1
+	}
+	p := v.cursorPosition()
+
+	switch cmd.action {
+	case "x", "X", "D", "C":
+		op, motion := 'd', "l"
+		switch cmd.action {
+		case "X":
+			motion = "h"
+		case "D":
+			motion = "$"
+		case "C":
+			op, motion = 'c', "$"
+		}
+		e.operate(v, vimCommand{register: cmd.register, count: cmd.count, op: op, motion: motion})
+	case "i", "a", "I", "A", "o", "O":
+		v.ClearSelection()
+		e.mode = VimInsert
+		switch cmd.action {
+		case "a":
+			v.moveCursorTo(v.clampPosition(position{p.x + 1, p.y}))
+		case "I":
+			v.moveCursorTo(position{0, p.y})
+		case "A":
+			v.moveCursorTo(v.lineEnd(p))
+		case "o":
+			v.moveCursorTo(v.lineEnd(p))
+			v.EditNewLine()
+		case "O":
+			v.moveCursorTo(position{0, p.y})
+			v.EditNewLine()
+			v.moveCursorTo(position{0, p.y})
+		}
+	case "p", "P":
+		e.put(v, cmd.register, cmd.action == "p", n)
+	case "u":
+		for i := 0; i < n; i++ {
+			v.Undo()
+		}
+	case ".":
+		keys := e.lastChange
+		if cmd.count > 0 {
+			keys = replaceVimCount(keys, cmd.count)
+		}
+		for _, k := range keys {
+			v.refreshViewLines()
+			e.Edit(v, k.key, k.ch, k.mod)
+		}
+	case "v":
+		if e.mode == VimVisual {
+			e.mode = VimNormal
+			v.ClearSelection()
+			break
+		}
+		e.mode, e.visual = VimVisual, p
+	case "/", "?", ":":
+		e.mode, e.cmdline = VimCommandLine, []rune(cmd.action)
+	}
+}
+
+// replaceVimCount returns the keystrokes of a command with its counts
+// replaced by count.
+func replaceVimCount(keys []vimKey, count int) []vimKey {
+	i := 0
+	if len(keys) >= 2 && keys[0].ch == '"' {
+		i = 2
+	}
+	var out []vimKey
+	out = append(out, keys[:i]...)
+	for _, ch := range strconv.Itoa(count) {
+		out = append(out, vimKey{ch: ch})
+	}
+	i = skipVimCount(keys, i)
+	if i < len(keys) && strings.ContainsRune(vimOperators, keys[i].ch) {
+		out = append(out, keys[i])
+		i = skipVimCount(keys, i+1)
+	}
+	return append(out, keys[i:]...)
+}
+
+// skipVimCount returns the index of the first keystroke after the count that
+// starts at keys[i].
+func skipVimCount(keys []vimKey, i int) int {
+	runes := make([]rune, len(keys))
+	for j, k := range keys {
+		runes[j] = k.ch
+	}
+	_, i = parseVimCount(runes, i)
+	return i
+}
+
+// Paste inserts the text pasted in the terminal at the cursor position, as a
+// single change, in any mode. It is never taken as commands.
+func (e *VimEditor) Paste(v *View, text string) {
+	e.reset()
+	if e.mode == VimVisual {
+		e.mode = VimNormal
+		v.ClearSelection()
+	}
+
+	v.beginEdit(editOther)
+	v.EditWriteString(text)
+	v.endEdit()
+
+	if e.mode == VimNormal {
+		e.clampCursor(v)
+	}
+}
+
+// put inserts the content of the register count times after the cursor, or
+// before it if after is false.
+func (e *VimEditor) put(v *View, reg rune, after bool, count int) {
+	r := e.register(v, reg)
+	if r.text == "" {
+		return
+	}
+	text := strings.Repeat(r.text, count)
+
+	v.beginEdit(editOther)
+	defer v.endEdit()
+
+	p := v.cursorPosition()
+	if r.linewise {
+		if after {
+			v.moveCursorTo(v.lineEnd(p))
+			v.EditWriteString("\n" + strings.TrimSuffix(text, "\n"))
+			v.moveCursorTo(position{0, p.y + 1})
+		} else {
+			v.moveCursorTo(position{0, p.y})
+			v.EditWriteString(text)
+			v.moveCursorTo(position{0, p.y})
+		}
+		return
+	}
+
+	if after && p.y < len(v.lines) && p.x < len(v.lines[p.y]) {
+		v.moveCursorTo(position{p.x + 1, p.y})
+	}
+	v.EditWriteString(text)
+	if q := v.cursorPosition(); q.x > 0 {
+		v.moveCursorTo(position{q.x - 1, q.y})
+	}
+}
+
+// register returns the content of the register reg. The registers '+' and
+// '*' are the clipboard of the view.
+func (e *VimEditor) register(v *View, reg rune) vimRegister {
+	if (reg == '+' || reg == '*') && v.Clipboard != nil {
+		text, err := v.Clipboard.Paste()
+		if err != nil {
+			return vimRegister{}
+		}
+		return vimRegister{text: text, linewise: strings.HasSuffix(text, "\n")}
+	}
+	if reg == 0 {
+		reg = '"'
+	}
+	return e.registers[reg]
+}
+
+// setRegister stores text in the register reg and the unnamed register.
+func (e *VimEditor) setRegister(v *View, reg rune, text string, linewise bool) {
+	if e.registers == nil {
+		e.registers = make(map[rune]vimRegister)
+	}
+	r := vimRegister{text: text, linewise: linewise}
+	if (reg == '+' || reg == '*') && v.Clipboard != nil {
+		v.Clipboard.Copy(text)
+	} else if reg != 0 {
+		e.registers[reg] = r
+	}
+	e.registers['"'] = r
+}
+
+// clampCursor keeps the cursor on a character of the line, like vim does
+// in normal mode.
+func (e *VimEditor) clampCursor(v *View) {
+	p := v.cursorPosition()
+	if p.y < len(v.lines) && p.x > 0 && p.x >= len(v.lines[p.y]) {
+		v.moveCursorTo(position{len(v.lines[p.y]) - 1, p.y})
+	}
+}
+
+// updateVisual updates the selection of the view in visual mode. The
+// characters under the start and the cursor are selected.
+func (e *VimEditor) updateVisual(v *View) {
+	p := v.cursorPosition()
+	start, end := e.visual, p
+	if end.before(start) {
+		start, end = end, start
+	}
+	end = v.clampPosition(position{end.x + 1, end.y})
+	v.sel = selection{active: true, anchor: start, head: end}
+}
+
+// clampLine returns p moved to the closest line of the internal buffer, with
+// its column limited to the length of the line.
+func (v *View) clampLine(p position) position {
+	if p.y < 0 {
+		p.y = 0
+	}
+	if p.y >= len(v.lines) {
+		p.y = len(v.lines) - 1
+	}
+	if p.y < 0 {
+		return position{}
+	}
+	if p.x > len(v.lines[p.y]) {
+		p.x = len(v.lines[p.y])
+	}
+	return p
+}
+
+// wordEnd returns the end of the word after p, which can be in the following
+// lines.
+func (v *View) wordEnd(p position) position {
+	next := func(p position) position {
+		if p.x+1 < len(v.lines[p.y]) {
+			return position{p.x + 1, p.y}
+		}
+		if p.y+1 < len(v.lines) {
+			return position{0, p.y + 1}
+		}
+		return p
+	}
+	isSpace := func(p position) bool {
+		line := v.lines[p.y]
+		return p.x >= len(line) || indexFunc(line[p.x].chr)
+	}
+
+	if p.y >= len(v.lines) {
+		return p
+	}
+	q := next(p)
+	for isSpace(q) {
+		n := next(q)
+		if n == q {
+			return q
+		}
+		q = n
+	}
+	line := v.lines[q.y]
+	for q.x+1 < len(line) && !indexFunc(line[q.x+1].chr) {
+		q.x++
+	}
+	return q
+}
+
+// findText returns the position of the next occurrence of text in the
+// internal buffer after p, or before it if back is true. The search wraps
+// around the end of the buffer.
+func (v *View) findText(text string, p position, back bool) (position, bool) {
+	pattern := []rune(text)
+	n := len(v.lines)
+	if n == 0 || len(pattern) == 0 {
+		return p, false
+	}
+
+	for i := 0; i <= n; i++ {
+		y := p.y + i
+		if back {
+			y = p.y - i
+		}
+		y = ((y % n) + n) % n
+
+		matches := findRunes(v.lines[y], pattern)
+		if back {
+			for j := len(matches) - 1; j >= 0; j-- {
+				x := matches[j]
+				if i == 0 && x >= p.x || i == n && x < p.x {
+					continue
+				}
+				return position{x, y}, true
+			}
+		} else {
+			for _, x := range matches {
+				if i == 0 && x <= p.x || i == n && x > p.x {
+					continue
+				}
+				return position{x, y}, true
+			}
+		}
+	}
+	return p, false
+}
+
+// findRunes returns the indexes of the occurrences of pattern in line.
+func findRunes(line []cell, pattern []rune) []int {
+	var idxs []int
+	for x := 0; x+len(pattern) <= len(line); x++ {
+		match := true
+		for i, r := range pattern {
+			if line[x+i].chr != r {
+				match = false
+				break
+			}
+		}
+		if match {
+			idxs = append(idxs, x)
+		}
+	}
+	return idxs
+}
@@ -0,0 +1,160 @@
+// Copyright 2014 The gocui Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// newVimView returns an editable view with the given text and a VimEditor.
+func newVimView(t *testing.T, text string) (*Gui, *View, *VimEditor) {
+	g := &Gui{maxX: 80, maxY: 24}
+	v, err := g.SetView("main", 0, 0, 40, 20)
+	if err != ErrUnknownView {
+		t.Fatal(err)
+	}
+	e := NewVimEditor()
+	v.Editable, v.Editor = true, e
+	fmt.Fprint(v, text)
+	return g, v, e
+}
+
+// vimType feeds keys to the editor. "<esc>", "<cr>" and "<c-r>" stand for
+// Esc, Enter and Ctrl-R.
+func vimType(e *VimEditor, v *View, keys string) {
+	special := map[string]Key{"<esc>": KeyEsc, "<cr>": KeyEnter, "<c-r>": KeyCtrlR}
+	for keys != "" {
+		key, ch, n := Key(0), rune(0), 1
+		for s, k := range special {
+			if strings.HasPrefix(keys, s) {
+				key, n = k, len(s)
+			}
+		}
+		if key == 0 {
+			ch = []rune(keys)[0]
+			n = len(string(ch))
+		}
+		keys = keys[n:]
+		v.refreshViewLines()
+		e.Edit(v, key, ch, ModNone)
+	}
+}
+
+func TestVimEditor(t *testing.T) {
+	tests := []struct {
+		text string
+		keys string
+		want string
+		x, y int
+	}{
+		// motions
+		{"foo bar baz", "w", "foo bar baz", 4, 0},
+		{"foo bar baz", "2w", "foo bar baz", 8, 0},
+		{"foo bar baz", "$b", "foo bar baz", 8, 0},
+		{"foo bar", "e", "foo bar", 2, 0},
+		{"foo bar", "$0", "foo bar", 0, 0},
+		{"a\nb\nc", "G", "a\nb\nc", 0, 2},
+		{"a\nb\nc", "Ggg", "a\nb\nc", 0, 0},
+		{"a\nb\nc", "2G", "a\nb\nc", 0, 1},
+
+		// motions at the edges of the buffer
+		{"a\nb\nc", "k", "a\nb\nc", 0, 0},
+		{"a\nb\nc", "Gj", "a\nb\nc", 0, 2},
+		{"a\nb\nc", "5j", "a\nb\nc", 0, 2},
+		{"a\nb\nc", "Gdj", "a\nb\nc", 0, 2},
+		{"a\nb\nc", "ggdk", "a\nb\nc", 0, 0},
+		{"a\nb\nc", "jdj", "a", 0, 0},
+		{"a\nb\nc", "Gdk", "a", 0, 0},
+		{"abc", "$l", "abc", 2, 0},
+
+		// operators and counts
+		{"foo bar baz", "dw", "bar baz", 0, 0},
+		{"foo bar baz", "2dw", "baz", 0, 0},
+		{"foo bar baz", "d2w", "baz", 0, 0},
+		{"foo bar", "cwqux<esc>", "qux bar", 2, 0},
+		{"foo bar", "wD", "foo ", 3, 0},
+		{"abcdef", "3x", "def", 0, 0},
+		{"abcdef", "$X", "abcdf", 4, 0},
+		{"a\nb\nc", "jdd", "a\nc", 0, 1},
+		{"a\nb\nc", "2dd", "c", 0, 0},
+		{"a\nb\nc", "Gdd", "a\nb", 0, 1},
+		{"a\nb\nc", "jdG", "a", 0, 0},
+		{"a\nb\nc", "ccx<esc>", "x\nb\nc", 0, 0},
+
+		// registers and put
+		{"a\nb", "yyp", "a\na\nb", 0, 1},
+		{"a\nb", "yyjP", "a\na\nb", 0, 1},
+		{"a\nb", "yy2p", "a\na\na\nb", 0, 1},
+		{"ab", "xp", "ba", 1, 0},
+		{"foo bar", "yw$p", "foo barfoo ", 10, 0},
+		{"a\nb", "\"ayyjdd\"ap", "a\na", 0, 1},
+
+		// insert
+		{"bc", "ia<esc>", "abc", 0, 0},
+		{"ab", "ax<esc>", "axb", 1, 0},
+		{"a", "ob<esc>", "a\nb", 0, 1},
+		{"b", "Oa<esc>", "a\nb", 0, 0},
+
+		// repetition
+		{"a\nb\nc\nd", "dd.", "c\nd", 0, 0},
+		{"a\nb\nc\nd\ne", "dd3.", "e", 0, 0},
+		{"a b c d e f", "d2w.", "e f", 0, 0},
+		{"a b c d e f", "dw3.", "e f", 0, 0},
+		{"abcdef", "x.", "cdef", 0, 0},
+		{"foo bar", "cwx<esc>w.", "x x", 2, 0},
+
+		// undo and redo
+		{"foo bar", "dwu", "foo bar", 0, 0},
+		{"a\nb\nc", "dd.uu", "a\nb\nc", 0, 0},
+		{"foo bar", "dwu<c-r>", "bar", 0, 0},
+		{"abc", "ixy<esc>u", "abc", 0, 0},
+
+		// visual mode
+		{"foo bar", "vld", "o bar", 0, 0},
+		{"foo bar", "vey$p", "foo barfoo", 9, 0},
+	}
+
+	for _, tt := range tests {
+		_, v, e := newVimView(t, tt.text)
+		vimType(e, v, tt.keys)
+		got := strings.Join(v.BufferLines(), "\n")
+		p := v.cursorPosition()
+		if got != tt.want || p.x != tt.x || p.y != tt.y {
+			t.Errorf("%q, %q: got %q at (%d, %d), want %q at (%d, %d)",
+				tt.text, tt.keys, got, p.x, p.y, tt.want, tt.x, tt.y)
+		}
+	}
+}
+
+func TestVimEditorPaste(t *testing.T) {
+	for _, keys := range []string{"", "i", "v", "d"} {
+		g, v, e := newVimView(t, "abc")
+		if _, err := g.SetCurrentView("main"); err != nil {
+			t.Fatal(err)
+		}
+		vimType(e, v, keys)
+		mode := e.Mode()
+		if mode == VimVisual {
+			mode = VimNormal
+		}
+
+		if err := g.onPaste("ggdG\tx\ny"); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := strings.Join(v.BufferLines(), "\n"), "ggdG\tx\nyabc"; got != want {
+			t.Errorf("paste after %q: got %q, want %q", keys, got, want)
+		}
+		if e.Mode() != mode {
+			t.Errorf("paste after %q: got mode %v, want %v", keys, e.Mode(), mode)
+		}
+
+		vimType(e, v, "<esc>u")
+		if got, want := strings.Join(v.BufferLines(), "\n"), "abc"; got != want {
+			t.Errorf("undo paste after %q: got %q, want %q", keys, got, want)
+		}
+	}
+}